@@ -62,14 +62,30 @@ var (
 	AddReaction       string = Scheme + Domain.Api + "/ILoyaltyRewardsService/AddReaction/v1"       // 添加反应/表情到指定内容
 
 	// 市场交易相关API端点
-	GetMyListings       string = Scheme + Domain.Community + "/market/mylistings"     // 获取用户的上架列表
-	RemoveMyListings    string = Scheme + Domain.Community + "/market/removelisting"  // 删除用户的已上架或待确认的物品
-	GetInventory        string = Scheme + Domain.Community + "/inventory"             // 获取用户库存
-	PutList             string = Scheme + Domain.Community + "/market/sellitem"       // 上架物品
-	GetConfirmationList string = Scheme + Domain.Community + "/mobileconf/getlist"    // 获取待确认列表
-	Confirmation        string = Scheme + Domain.Community + "/mobileconf/ajaxop"     // 确认上架
-	BuyListing          string = Scheme + Domain.Community + "/market/buylisting"     // 购买物品
-	CreateOrder         string = Scheme + Domain.Community + "/market/createbuyorder" // 创建订单
+	MarketIndex         string = Scheme + Domain.Community + "/market/"                         // 社区市场首页
+	GetMyListings       string = Scheme + Domain.Community + "/market/mylistings"               // 获取用户的上架列表
+	RemoveMyListings    string = Scheme + Domain.Community + "/market/removelisting"            // 删除用户的已上架或待确认的物品
+	GetInventory        string = Scheme + Domain.Community + "/inventory"                       // 获取用户库存
+	Profiles            string = Scheme + Domain.Community + "/profiles"                        // 用户个人资料页，拼接 /{steamid}/inventory/ 获取库存页面
+	EconomyImage        string = Scheme + "community.cloudflare.steamstatic.com/economy/image/" // 物品图标地址前缀，拼接 icon_url 使用
+	PutList             string = Scheme + Domain.Community + "/market/sellitem"                 // 上架物品
+	GetConfirmationList string = Scheme + Domain.Community + "/mobileconf/getlist"              // 获取待确认列表
+	Confirmation        string = Scheme + Domain.Community + "/mobileconf/ajaxop"               // 确认上架
+	BuyListing          string = Scheme + Domain.Community + "/market/buylisting"               // 购买物品
+	CreateOrder         string = Scheme + Domain.Community + "/market/createbuyorder"           // 创建订单
+	CancelBuyOrder      string = Scheme + Domain.Community + "/market/cancelbuyorder"           // 取消订单
+	GetBuyOrderStatus   string = Scheme + Domain.Community + "/market/getbuyorderstatus/"       // 查询订购单成交进度
+	GetMarketHistory    string = Scheme + Domain.Community + "/market/myhistory/render"         // 获取市场交易历史
+	MarketListings      string = Scheme + Domain.Community + "/market/listings"                 // 物品市场页面，拼接 /{appid}/{market_hash_name}
+	ItemOrdersHistogram string = Scheme + Domain.Community + "/market/itemordershistogram"      // 获取物品订单簿
+
+	// 交易报价相关API端点
+	TradeOffer            string = Scheme + Domain.Community + "/tradeoffer/"                      // 报价页面，拼接 {id}/accept、{id}/decline、{id}/cancel 处理报价
+	SendTradeOffer        string = Scheme + Domain.Community + "/tradeoffer/new/send"              // 发送报价
+	GetTradeOffers        string = Scheme + Domain.Api + "/IEconService/GetTradeOffers/v1/"        // 获取报价列表
+	GetTradeOffer         string = Scheme + Domain.Api + "/IEconService/GetTradeOffer/v1/"         // 获取单个报价
+	GetTradeHoldDurations string = Scheme + Domain.Api + "/IEconService/GetTradeHoldDurations/v1/" // 获取交易暂挂时长
+	GetTradeHistory       string = Scheme + Domain.Api + "/IEconService/GetTradeHistory/v1/"       // 获取交易历史
 
 	// 游戏更新
 	GetGameUpdateInofs    string = Scheme + Domain.Store + "/news/app" // 获取游戏更新信息
@@ -81,11 +97,17 @@ var (
 	RemoveFriendAjax string = Scheme + Domain.Community + "/actions/RemoveFriendAjax" // 通过好友码删除好友
 	Ajaxresolveusers string = Scheme + Domain.Community + "/actions/ajaxresolveusers" // 查看好友信息
 
+	// 商店相关API端点
+	GetStoreItems string = Scheme + Domain.Api + "/IStoreBrowseService/GetItems/v1" // 批量获取商店物品详情
+
 	// 购物车相关API端点
-	ClearCart      string = Scheme + Domain.Api + "/IAccountCartService/DeleteCart/v1" // 清空购物车
-	CartIndex      string = Scheme + Domain.Store + "/cart/"
-	AddItemsToCart string = Scheme + Domain.Api + "/IAccountCartService/AddItemsToCart/v1"
-	ValidateCart   string = Scheme + Domain.Api + "/ICheckoutService/ValidateCart/v1"
+	ClearCart          string = Scheme + Domain.Api + "/IAccountCartService/DeleteCart/v1" // 清空购物车
+	CartIndex          string = Scheme + Domain.Store + "/cart/"
+	AddItemsToCart     string = Scheme + Domain.Api + "/IAccountCartService/AddItemsToCart/v1"
+	GetCart            string = Scheme + Domain.Api + "/IAccountCartService/GetCart/v1"            // 获取购物车
+	ModifyLineItem     string = Scheme + Domain.Api + "/IAccountCartService/ModifyLineItem/v1"     // 修改购物车商品
+	RemoveItemFromCart string = Scheme + Domain.Api + "/IAccountCartService/RemoveItemFromCart/v1" // 删除购物车商品
+	ValidateCart       string = Scheme + Domain.Api + "/ICheckoutService/ValidateCart/v1"
 
 	// 订单相关 API 端点
 	InitTransaction     string = Scheme + Domain.CheckOut + "/checkout/inittransaction/"
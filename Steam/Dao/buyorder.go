@@ -0,0 +1,326 @@
+package Dao
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JovanniChen/SteamDB/Steam/Errors"
+	"github.com/JovanniChen/SteamDB/Steam/Logger"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+)
+
+// defaultBuyOrderPollInterval 默认轮询订购单成交进度的间隔
+const defaultBuyOrderPollInterval = time.Minute
+
+// BuyOrderManager 按 market_hash_name 管理订购单
+// 创建订购单前检查钱包余额和预算，定期查询成交进度并将成交记录保存到数据库
+type BuyOrderManager struct {
+	dao     *Dao
+	options Model.BuyOrderManagerOptions
+
+	mu      sync.Mutex
+	opMu    sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+	running bool
+}
+
+// NewBuyOrderManager 创建订购单管理器
+func (d *Dao) NewBuyOrderManager(opts *Model.BuyOrderManagerOptions) *BuyOrderManager {
+	options := Model.BuyOrderManagerOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaultBuyOrderPollInterval
+	}
+	return &BuyOrderManager{
+		dao:     d,
+		options: options,
+	}
+}
+
+// Start 开始后台轮询成交进度，重复调用无效果
+func (m *BuyOrderManager) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.running {
+		return
+	}
+	m.running = true
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	go m.loop(m.stop, m.done)
+}
+
+// Stop 停止轮询并等待当前轮询结束
+func (m *BuyOrderManager) Stop() {
+	m.mu.Lock()
+	if !m.running {
+		m.mu.Unlock()
+		return
+	}
+	m.running = false
+	close(m.stop)
+	done := m.done
+	m.mu.Unlock()
+
+	<-done
+}
+
+func (m *BuyOrderManager) loop(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(m.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Poll(); err != nil {
+			Logger.Errorf("轮询订购单失败，用户: [%s], 错误: %v", m.dao.GetUsername(), err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Place 为物品创建订购单，price 为单价
+// 同一物品已有进行中的订购单时返回 ErrBuyOrderExists，超出余额或预算时返回 ErrBuyOrderBudgetExceeded，
+// 价格或预算的货币与钱包货币不一致时返回 ErrBuyOrderCurrency
+func (m *BuyOrderManager) Place(appID int, marketHashName string, price Model.Money, quantity int64) (*Model.ManagedBuyOrder, error) {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	steamID := m.dao.GetSteamID()
+	active, err := GetManagedBuyOrders(steamID, true)
+	if err != nil {
+		return nil, err
+	}
+	if findManagedBuyOrder(active, appID, marketHashName) != nil {
+		return nil, fmt.Errorf("%w: %s", Errors.ErrBuyOrderExists, marketHashName)
+	}
+
+	return m.place(steamID, active, appID, marketHashName, price, quantity)
+}
+
+// Update 以新的单价和数量替换物品的订购单
+// Steam 同一物品只允许一个进行中的订购单，因此只能先取消原订购单再重新创建；
+// 原订购单已取消但新订购单创建失败时返回 ErrBuyOrderReplaceFailed，调用方需要自行重新挂单
+func (m *BuyOrderManager) Update(appID int, marketHashName string, price Model.Money, quantity int64) (*Model.ManagedBuyOrder, error) {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	steamID := m.dao.GetSteamID()
+	active, err := GetManagedBuyOrders(steamID, true)
+	if err != nil {
+		return nil, err
+	}
+	old := findManagedBuyOrder(active, appID, marketHashName)
+	if old == nil {
+		return nil, fmt.Errorf("%w: %s", Errors.ErrBuyOrderNotFound, marketHashName)
+	}
+
+	// 原订购单会被取消，计算预算时不再占用
+	others := make([]Model.ManagedBuyOrder, 0, len(active))
+	for _, order := range active {
+		if order.BuyOrderID != old.BuyOrderID {
+			others = append(others, order)
+		}
+	}
+	if err := m.checkBudget(others, price.Mul(quantity)); err != nil {
+		return nil, err
+	}
+
+	if err := m.cancel(steamID, old); err != nil {
+		return nil, err
+	}
+
+	order, err := m.place(steamID, others, appID, marketHashName, price, quantity)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 原订购单 %s（%s）: %w", Errors.ErrBuyOrderReplaceFailed, old.BuyOrderID, marketHashName, err)
+	}
+	return order, nil
+}
+
+// Cancel 取消物品进行中的订购单
+func (m *BuyOrderManager) Cancel(appID int, marketHashName string) error {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	steamID := m.dao.GetSteamID()
+	active, err := GetManagedBuyOrders(steamID, true)
+	if err != nil {
+		return err
+	}
+	order := findManagedBuyOrder(active, appID, marketHashName)
+	if order == nil {
+		return fmt.Errorf("%w: %s", Errors.ErrBuyOrderNotFound, marketHashName)
+	}
+
+	return m.cancel(steamID, order)
+}
+
+// Poll 查询所有进行中订购单的成交进度，返回本次新增的成交记录
+func (m *BuyOrderManager) Poll() ([]Model.BuyOrderFill, error) {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	steamID := m.dao.GetSteamID()
+	active, err := GetManagedBuyOrders(steamID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	fills := make([]Model.BuyOrderFill, 0)
+	for i := range active {
+		order := &active[i]
+		status, err := m.dao.GetBuyOrderStatus(order.BuyOrderID)
+		if err != nil {
+			return fills, err
+		}
+
+		now := time.Now()
+		for _, purchase := range status.Purchases {
+			fill := Model.BuyOrderFill{
+				BuyOrderID:     order.BuyOrderID,
+				ListingID:      purchase.ListingID,
+				AppID:          purchase.AppID,
+				ContextID:      purchase.ContextID,
+				AssetID:        purchase.AssetID,
+				MarketHashName: order.MarketHashName,
+				Price:          Model.Money{Amount: purchase.PriceTotal, Currency: order.Price.Currency},
+				FilledAt:       now,
+			}
+			inserted, err := SaveBuyOrderFill(steamID, &fill)
+			if err != nil {
+				return fills, err
+			}
+			if inserted {
+				Logger.Infof("订购单 %s 成交: %s，价格: %s", order.BuyOrderID, order.MarketHashName, fill.Price)
+				fills = append(fills, fill)
+			}
+		}
+
+		state := order.State
+		switch {
+		case status.QuantityRemaining <= 0:
+			state = Model.ManagedBuyOrderFilled
+		case !status.Active:
+			state = Model.ManagedBuyOrderCancelled
+		}
+		if state == order.State && status.QuantityRemaining == order.QuantityRemaining {
+			continue
+		}
+
+		order.State = state
+		order.QuantityRemaining = status.QuantityRemaining
+		order.UpdatedAt = now
+		if err := SaveManagedBuyOrder(steamID, order); err != nil {
+			return fills, err
+		}
+	}
+
+	return fills, nil
+}
+
+// Orders 获取管理器创建的订购单，activeOnly 为 true 时只返回进行中的订购单
+func (m *BuyOrderManager) Orders(activeOnly bool) ([]Model.ManagedBuyOrder, error) {
+	return GetManagedBuyOrders(m.dao.GetSteamID(), activeOnly)
+}
+
+// Fills 获取所有订购单的成交记录
+func (m *BuyOrderManager) Fills() ([]Model.BuyOrderFill, error) {
+	return GetBuyOrderFills(m.dao.GetSteamID(), "")
+}
+
+func (m *BuyOrderManager) place(steamID uint64, active []Model.ManagedBuyOrder, appID int, marketHashName string, price Model.Money, quantity int64) (*Model.ManagedBuyOrder, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("订购数量无效: %d", quantity)
+	}
+	if err := m.checkBudget(active, price.Mul(quantity)); err != nil {
+		return nil, err
+	}
+
+	buyOrderID, err := m.dao.PlaceBuyOrder(appID, marketHashName, price, quantity, m.options.MaFileContent)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	order := &Model.ManagedBuyOrder{
+		BuyOrderID:        buyOrderID,
+		AppID:             appID,
+		MarketHashName:    marketHashName,
+		Price:             price,
+		Quantity:          quantity,
+		QuantityRemaining: quantity,
+		State:             Model.ManagedBuyOrderActive,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := SaveManagedBuyOrder(steamID, order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (m *BuyOrderManager) cancel(steamID uint64, order *Model.ManagedBuyOrder) error {
+	if err := m.dao.CancelBuyOrder(order.BuyOrderID); err != nil {
+		return err
+	}
+
+	order.State = Model.ManagedBuyOrderCancelled
+	order.UpdatedAt = time.Now()
+	return SaveManagedBuyOrder(steamID, order)
+}
+
+// checkBudget 检查新增金额加上进行中订购单的占用是否超出钱包余额和预算
+// 新增金额或预算的货币与钱包货币不一致时返回 ErrBuyOrderCurrency，避免不同货币的金额直接比较
+func (m *BuyOrderManager) checkBudget(active []Model.ManagedBuyOrder, amount Model.Money) error {
+	wallet, err := m.dao.FetchWalletInfo()
+	if err != nil {
+		return fmt.Errorf("获取钱包余额失败: %w", err)
+	}
+
+	currency := Model.Currency(wallet.WalletCurrency)
+	if amount.Currency != currency {
+		return fmt.Errorf("%w: 价格货币 %s，钱包货币 %s", Errors.ErrBuyOrderCurrency, amount.Currency, currency)
+	}
+	if !m.options.Budget.IsZero() && m.options.Budget.Currency != currency {
+		return fmt.Errorf("%w: 预算货币 %s，钱包货币 %s", Errors.ErrBuyOrderCurrency, m.options.Budget.Currency, currency)
+	}
+
+	limit := wallet.WalletBalance
+	if !m.options.Budget.IsZero() && m.options.Budget.Amount < limit {
+		limit = m.options.Budget.Amount
+	}
+
+	var committed int64
+	for _, order := range active {
+		committed += order.Committed().Amount
+	}
+
+	if committed+amount.Amount > limit {
+		return fmt.Errorf("%w: 已占用 %s，新增 %s，上限 %s", Errors.ErrBuyOrderBudgetExceeded,
+			Model.NewMoney(committed, amount.Currency), amount, Model.NewMoney(limit, amount.Currency))
+	}
+
+	return nil
+}
+
+// findManagedBuyOrder 按物品查找订购单
+func findManagedBuyOrder(orders []Model.ManagedBuyOrder, appID int, marketHashName string) *Model.ManagedBuyOrder {
+	for i := range orders {
+		if orders[i].AppID == appID && orders[i].MarketHashName == marketHashName {
+			return &orders[i]
+		}
+	}
+	return nil
+}
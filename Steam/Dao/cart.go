@@ -16,8 +16,10 @@ import (
 	"github.com/JovanniChen/SteamDB/Steam/Model"
 	"github.com/JovanniChen/SteamDB/Steam/Param"
 	"github.com/JovanniChen/SteamDB/Steam/Protoc"
+	"github.com/JovanniChen/SteamDB/Steam/Utils"
 	"github.com/antchfx/htmlquery"
 	"golang.org/x/net/html"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -44,8 +46,91 @@ func (d *Dao) ClearCart() error {
 	return nil
 }
 
-func (d *Dao) GetCart() error {
-	req, err := d.Request(http.MethodGet, Constants.CartIndex, nil)
+// GetCart 获取购物车内容（商品、小计和校验结果）
+func (d *Dao) GetCart() (*Protoc.Cart, error) {
+	getCartSend := &Protoc.GetCartSend{
+		UserCountry: d.GetCountryCode(),
+	}
+
+	getCartReceive := &Protoc.GetCartReceive{}
+	if err := d.accountCartRequest(http.MethodGet, Constants.GetCart, getCartSend, getCartReceive, "GetCart"); err != nil {
+		return nil, err
+	}
+
+	return cartOrEmpty(getCartReceive.Cart), nil
+}
+
+// ModifyLineItem 修改购物车中商品的礼物信息、私密标记和优惠券，返回修改后的购物车
+func (d *Dao) ModifyLineItem(lineItemID uint64, item Model.ModifyLineItem) (*Protoc.Cart, error) {
+	// 与加入购物车使用相同的礼物判断规则，设置了收礼人也视为礼物
+	gift := Model.AddCartItem{
+		IsGift:          item.IsGift,
+		AccountidGiftee: item.AccountidGiftee,
+		GifteeSteamID:   item.GifteeSteamID,
+		GifteeName:      item.GifteeName,
+		Message:         item.Message,
+		Sentiment:       item.Sentiment,
+		Signature:       item.Signature,
+		ScheduledSendAt: item.ScheduledSendAt,
+	}
+	modifyCartSend := &Protoc.ModifyCartSend{
+		LineItemId:     lineItemID,
+		UserCountry:    d.GetCountryCode(),
+		ApplyGidcoupon: item.CouponGID,
+		Flag: &Protoc.Flag{
+			IsGift:    gift.Gift(),
+			IsPrivate: item.IsPrivate,
+		},
+	}
+	if gift.Gift() {
+		modifyCartSend.GiftInfo = cartGiftInfo(gift)
+	}
+
+	modifyCartReceive := &Protoc.ModifyCartReceive{}
+	if err := d.accountCartRequest(http.MethodPost, Constants.ModifyLineItem, modifyCartSend, modifyCartReceive, "ModifyLineItem"); err != nil {
+		return nil, err
+	}
+
+	return cartOrEmpty(modifyCartReceive.Cart), nil
+}
+
+// RemoveLineItem 从购物车中删除商品，返回删除后的购物车
+func (d *Dao) RemoveLineItem(lineItemID uint64) (*Protoc.Cart, error) {
+	removeCartSend := &Protoc.RemoveCartSend{
+		LineItemId:  lineItemID,
+		UserCountry: d.GetCountryCode(),
+	}
+
+	removeCartReceive := &Protoc.RemoveCartReceive{}
+	if err := d.accountCartRequest(http.MethodPost, Constants.RemoveItemFromCart, removeCartSend, removeCartReceive, "RemoveLineItem"); err != nil {
+		return nil, err
+	}
+
+	return cartOrEmpty(removeCartReceive.Cart), nil
+}
+
+// accountCartRequest 调用 IAccountCartService 接口，请求和响应都使用protobuf
+func (d *Dao) accountCartRequest(method, apiURL string, send proto.Message, receive proto.Message, funcName string) error {
+	// 序列化为protobuf格式
+	data, err := proto.Marshal(send)
+	if err != nil {
+		return err
+	}
+
+	accessToken, _ := d.AccessToken()
+	params := Param.Params{}
+	params.SetString("access_token", accessToken)
+
+	var req *http.Request
+	if method == http.MethodGet {
+		params.SetString("input_protobuf_encoded", base64.StdEncoding.EncodeToString(data))
+		req, err = d.NewRequest(http.MethodGet, apiURL+"?"+params.ToUrl(), nil)
+	} else {
+		// 构建POST请求体参数(包含protobuf数据)
+		body := Param.Params{}
+		body.SetString("input_protobuf_encoded", base64.StdEncoding.EncodeToString(data))
+		req, err = d.NewRequest(http.MethodPost, apiURL+"?"+params.ToUrl(), strings.NewReader(body.Encode()))
+	}
 	if err != nil {
 		return err
 	}
@@ -56,14 +141,33 @@ func (d *Dao) GetCart() error {
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
+	if resp.StatusCode != http.StatusOK {
+		return Errors.ResponseError(resp.StatusCode)
+	}
+	if resp.Header.Get("X-Eresult") != "1" {
+		return fmt.Errorf("%s failed: %s", funcName, resp.Header.Get("X-Eresult"))
+	}
+
+	// 读取响应数据
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
 		return err
 	}
 
-	fmt.Println(resp.StatusCode)
-	fmt.Println(string(body))
-	return nil
+	// 空购物车会返回空响应
+	if buf.Len() == 0 {
+		return nil
+	}
+
+	return protoUnmarshalWithRetry(buf.Bytes(), receive, funcName, 3)
+}
+
+// cartOrEmpty 响应中没有购物车时返回空购物车
+func cartOrEmpty(cart *Protoc.Cart) *Protoc.Cart {
+	if cart == nil {
+		return &Protoc.Cart{}
+	}
+	return cart
 }
 
 func (d *Dao) AddItemToCart(addCartItems []Model.AddCartItem) error {
@@ -79,18 +183,12 @@ func (d *Dao) AddItemToCart(addCartItems []Model.AddCartItem) error {
 				Packageid: addCartItem.PackageID,
 			}
 		}
-		item.GiftInfo = &Protoc.GiftInfo{
-			AccountidGiftee: int32(addCartItem.AccountidGiftee),
-			GiftMessage: &Protoc.GiftMessage{
-				Gifteename: "",
-				Message:    addCartItem.Message,
-				Sentiment:  "",
-				Signature:  "",
-			},
-		}
 		item.Flag = &Protoc.Flag{
-			IsGift:    true,
-			IsPrivate: false,
+			IsGift:    addCartItem.Gift(),
+			IsPrivate: addCartItem.IsPrivate,
+		}
+		if addCartItem.Gift() {
+			item.GiftInfo = cartGiftInfo(addCartItem)
 		}
 
 		items = append(items, item)
@@ -153,31 +251,207 @@ func (d *Dao) AddItemToCart(addCartItems []Model.AddCartItem) error {
 	return nil
 }
 
-func (d *Dao) ValidateCart() error {
+// cartGiftInfo 根据商品的礼物选项生成礼物信息
+func cartGiftInfo(addCartItem Model.AddCartItem) *Protoc.GiftInfo {
+	accountID := addCartItem.AccountidGiftee
+	if accountID == 0 && addCartItem.GifteeSteamID != 0 {
+		accountID = Utils.SteamID64ToFriendCode(addCartItem.GifteeSteamID)
+	}
+
+	giftInfo := &Protoc.GiftInfo{
+		AccountidGiftee: int32(accountID),
+		GiftMessage: &Protoc.GiftMessage{
+			Gifteename: addCartItem.GifteeName,
+			Message:    addCartItem.Message,
+			Sentiment:  addCartItem.Sentiment,
+			Signature:  addCartItem.Signature,
+		},
+	}
+	if !addCartItem.ScheduledSendAt.IsZero() {
+		giftInfo.TimeScheduledSend = int32(addCartItem.ScheduledSendAt.Unix())
+	}
+	return giftInfo
+}
+
+// ValidateCart 校验购物车，返回每个商品无法购买的原因
+func (d *Dao) ValidateCart() (*Model.CartValidation, error) {
 	accessToken, _ := d.AccessToken()
 	params := Param.Params{}
 	params.SetString("access_token", accessToken)
 
 	req, err := d.NewRequest(http.MethodGet, Constants.ValidateCart+"?"+params.ToUrl(), nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	resp, err := d.RetryRequest(Constants.Tries, req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
+	if resp.StatusCode != http.StatusOK {
+		return nil, Errors.ResponseError(resp.StatusCode)
+	}
+	if resp.Header.Get("X-Eresult") != "1" {
+		return nil, fmt.Errorf("validate cart failed: %s", resp.Header.Get("X-Eresult"))
+	}
+
+	// 读取响应数据
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return nil, err
 	}
 
-	fmt.Println(resp.StatusCode)
-	fmt.Println("=====", string(body))
+	// 解析protobuf响应，空购物车会返回空响应
+	cartInfo := &Protoc.CartInfo{}
+	if buf.Len() > 0 {
+		if err := protoUnmarshalWithRetry(buf.Bytes(), cartInfo, "ValidateCart", 3); err != nil {
+			return nil, err
+		}
+	}
 
-	return nil
+	return cartValidation(cartInfo), nil
+}
+
+// RemoveInvalidLineItems 从购物车中删除校验结果中会导致结算失败的商品，返回删除后的购物车
+func (d *Dao) RemoveInvalidLineItems(validation *Model.CartValidation) (*Protoc.Cart, error) {
+	var cart *Protoc.Cart
+	for _, item := range validation.BlockingItems() {
+		var err error
+		cart, err = d.RemoveLineItem(item.LineItemID)
+		if err != nil {
+			return nil, fmt.Errorf("删除购物车商品 %d 失败: %w", item.LineItemID, err)
+		}
+	}
+
+	if cart == nil {
+		return d.GetCart()
+	}
+	return cart, nil
+}
+
+// cartValidation 将 ValidateCart 响应转换为校验结果
+func cartValidation(cartInfo *Protoc.CartInfo) *Model.CartValidation {
+	validation := &Model.CartValidation{
+		Valid: true,
+		Items: make([]Model.CartItemValidation, 0, len(cartInfo.CartItems)),
+	}
+	if len(cartInfo.EstimatedTotals) > 0 {
+		validation.Subtotal = cartPriceToMoney(cartInfo.EstimatedTotals[0].Subtotal)
+	}
+
+	for _, cartItem := range cartInfo.CartItems {
+		item := Model.CartItemValidation{
+			LineItemID:     cartItem.LineItemId,
+			PackageID:      cartItem.GetItemId().GetPackageid(),
+			BundleID:       cartItem.GetItemId().GetBundleid(),
+			Problems:       make([]Model.CartProblem, 0),
+			Subtotal:       cartPriceToMoney(cartItem.Subtotal),
+			PriceWhenAdded: cartPriceToMoney(cartItem.PriceWhenAdded),
+		}
+
+		item.ErrorFlags = cartItemFlags(cartItem, cartItemErrorsField, cartItem.Errors)
+		item.WarningFlags = cartItemFlags(cartItem, cartItemWarningsField, cartItem.Warnings)
+		if len(item.ErrorFlags) > 0 {
+			item.Problems = append(item.Problems, Model.CartProblemNotPurchasable)
+		}
+
+		// 价格变化以金额对比为准，不依赖 warnings 字段
+		if cartItem.PriceWhenAdded != nil && cartItem.Subtotal != nil && item.PriceWhenAdded.Amount != item.Subtotal.Amount {
+			item.Problems = append(item.Problems, Model.CartProblemPriceChanged)
+		}
+
+		if item.Blocking() {
+			validation.Valid = false
+		}
+		validation.Items = append(validation.Items, item)
+	}
+
+	return validation
+}
+
+// cartItemFlags 读取购物车商品 errors（字段号 5）或 warnings（字段号 6）中置位的标志
+// 这两个字段的布局尚未确认：按 uint32 解析时返回置位的比特位 value，以嵌套消息返回时（类型不符，
+// 被保留在未知字段中）返回非零的子字段号。错误标志含义未确认前一律视为无法购买，保证不会漏掉阻止结算的问题
+func cartItemFlags(cartItem *Protoc.CartItem, field protowire.Number, value uint32) []uint32 {
+	flags := make([]uint32, 0)
+	for bit := uint32(0); bit < 32; bit++ {
+		if value&(1<<bit) != 0 {
+			flags = append(flags, bit)
+		}
+	}
+
+	unknown := cartItem.ProtoReflect().GetUnknown()
+	for len(unknown) > 0 {
+		num, typ, n := protowire.ConsumeTag(unknown)
+		if n < 0 {
+			break
+		}
+		unknown = unknown[n:]
+		if num == field && typ == protowire.BytesType {
+			value, m := protowire.ConsumeBytes(unknown)
+			if m < 0 {
+				break
+			}
+			flags = append(flags, nestedFlags(value)...)
+			unknown = unknown[m:]
+			continue
+		}
+		m := protowire.ConsumeFieldValue(num, typ, unknown)
+		if m < 0 {
+			break
+		}
+		unknown = unknown[m:]
+	}
+
+	return flags
+}
+
+// 购物车商品 errors 和 warnings 的字段号
+const (
+	cartItemErrorsField   protowire.Number = 5
+	cartItemWarningsField protowire.Number = 6
+)
+
+// nestedFlags 返回嵌套消息中值非零的字段号，无法解析时视为一个未知标志
+func nestedFlags(b []byte) []uint32 {
+	flags := make([]uint32, 0)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return append(flags, 0)
+		}
+		b = b[n:]
+
+		set := true
+		if typ == protowire.VarintType {
+			v, m := protowire.ConsumeVarint(b)
+			if m < 0 {
+				return append(flags, uint32(num))
+			}
+			set = v != 0
+			n = m
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return append(flags, uint32(num))
+			}
+		}
+		b = b[n:]
+		if set {
+			flags = append(flags, uint32(num))
+		}
+	}
+	return flags
+}
+
+// cartPriceToMoney 将购物车价格转换为金额，currency_code 与 Steam 货币ID一致
+func cartPriceToMoney(price *Protoc.Price) Model.Money {
+	if price == nil {
+		return Model.Money{}
+	}
+	return Model.NewMoney(price.AmountInCents, Model.Currency(price.CurrencyCode))
 }
 
 func (d *Dao) GetProductByAppID(appID int) (map[string]Model.GamePurchaseAction, error) {
@@ -280,39 +554,28 @@ func extractCountryCode(doc *html.Node) string {
 }
 
 // extractFinalPrice 从HTML节点中提取最终价格
-func extractFinalPrice(priceNode *html.Node) string {
+func extractFinalPrice(priceNode *html.Node, currency Model.Currency) (Model.Money, bool) {
 	if priceNode == nil {
-		return ""
+		return Model.Money{}, false
 	}
 
-	// 优先从 data-price-final 属性提取（单位是分，需要除以100）
+	// 优先从 data-price-final 属性提取（单位是分）
 	priceFinal := htmlquery.SelectAttr(priceNode, "data-price-final")
 	if priceFinal != "" {
-		// 将分转换为元
-		if price, err := strconv.ParseFloat(priceFinal, 64); err == nil {
-			return fmt.Sprintf("%.2f", price/100.0)
+		if amount, err := strconv.ParseInt(priceFinal, 10, 64); err == nil {
+			return Model.NewMoney(amount, currency), true
 		}
 	}
 
 	// 如果没有 data-price-final，从文本内容提取
 	priceText := strings.TrimSpace(htmlquery.InnerText(priceNode))
 	if priceText != "" {
-		// 使用正则表达式提取价格数字
-		priceRegex := regexp.MustCompile(`(?i)(?:[￥$]|CNY|HKD|USD)\s*(\d+\.?\d*)`)
-		matches := priceRegex.FindStringSubmatch(priceText)
-		if len(matches) > 1 {
-			return matches[1]
-		}
-
-		// 如果没有找到带货币符号的价格，尝试只匹配数字
-		numberRegex := regexp.MustCompile(`(\d+\.?\d*)`)
-		matches = numberRegex.FindStringSubmatch(priceText)
-		if len(matches) > 1 {
-			return matches[1]
+		if price, err := Model.ParseMoney(priceText, currency); err == nil {
+			return price, true
 		}
 	}
 
-	return ""
+	return Model.Money{}, false
 }
 
 // CartInfo 购物车信息结构
@@ -450,13 +713,8 @@ func ParseGamePurchaseActions(htmlContent, url string) ([]Model.GamePurchaseActi
 		countryCode = "CN"
 	}
 
-	// 确定货币标志
-	moneyFlag := "￥"
-	if countryCode == "CN" {
-		moneyFlag = "￥"
-	} else if countryCode == "HK" {
-		moneyFlag = "HK$"
-	}
+	// 确定货币
+	currency := Model.CurrencyForCountry(countryCode)
 
 	// 判断URL类型
 	isAppURL := regexp.MustCompile(`/app/`).MatchString(url)
@@ -489,17 +747,17 @@ func ParseGamePurchaseActions(htmlContent, url string) ([]Model.GamePurchaseActi
 				priceNode = htmlquery.FindOne(wrapper, ".//div[contains(@class, 'discount_final_price')]")
 			}
 
-			finalPrice := extractFinalPrice(priceNode)
+			price, ok := extractFinalPrice(priceNode, currency)
 
 			// 如果缺少标题或价格，直接跳过该 wrapper
-			if gameName == "" || finalPrice == "" {
+			if gameName == "" || !ok {
 				continue
 			}
 
 			// 提取购物车信息
 			cartInfo := extractCartInfo(wrapper, isBundle)
 
-			if cartInfo != nil && cartInfo.ID != "" && finalPrice != "" {
+			if cartInfo != nil && cartInfo.ID != "" {
 				results = append(results, Model.GamePurchaseAction{
 					IsBundle: func() int {
 						if isBundle {
@@ -516,13 +774,14 @@ func ParseGamePurchaseActions(htmlContent, url string) ([]Model.GamePurchaseActi
 						}
 					}()),
 					GameName:       gameName,
-					FinalPrice:     finalPrice,
-					FinalPriceText: moneyFlag + " " + finalPrice,
+					FinalPrice:     price.Number(),
+					FinalPriceText: price.String(),
+					Price:          price,
 					CountryCode:    countryCode,
 					AddToCartIds:   cartInfo.ID,
 				})
 			} else {
-				fmt.Printf("解析失败 - gameName:%s, cartInfo:%+v, finalPrice:%s, url: %s\n", gameName, cartInfo, finalPrice, url)
+				fmt.Printf("解析失败 - gameName:%s, cartInfo:%+v, finalPrice:%s, url: %s\n", gameName, cartInfo, price, url)
 			}
 		}
 	} else {
@@ -549,17 +808,17 @@ func ParseGamePurchaseActions(htmlContent, url string) ([]Model.GamePurchaseActi
 				priceNode = htmlquery.FindOne(wrapper, ".//div[contains(@class, 'discount_final_price')]")
 			}
 
-			finalPrice := extractFinalPrice(priceNode)
+			price, ok := extractFinalPrice(priceNode, currency)
 
 			// 如果缺少标题或价格，直接跳过该 wrapper
-			if gameName == "" || finalPrice == "" {
+			if gameName == "" || !ok {
 				continue
 			}
 
 			// 提取购物车信息
 			cartInfo := extractCartInfo(wrapper, false)
 
-			if cartInfo != nil && cartInfo.ID != "" && finalPrice != "" {
+			if cartInfo != nil && cartInfo.ID != "" {
 				isBundle := cartInfo.Type == "addbundletocart"
 				results = append(results, Model.GamePurchaseAction{
 					IsBundle: func() int {
@@ -571,8 +830,9 @@ func ParseGamePurchaseActions(htmlContent, url string) ([]Model.GamePurchaseActi
 					}(),
 					BundleInfoTexts: convertToBundleInfo(cartInfo.Type),
 					GameName:        gameName,
-					FinalPrice:      finalPrice,
-					FinalPriceText:  moneyFlag + " " + finalPrice,
+					FinalPrice:      price.Number(),
+					FinalPriceText:  price.String(),
+					Price:           price,
 					CountryCode:     countryCode,
 					AddToCartIds:    cartInfo.ID,
 				})
@@ -144,59 +144,36 @@ type InitTransactionResponse struct {
 // bPreAuthOnly: 0
 // sessionid: 5bf319d1458a73814a5873be
 
+// InitTransaction 使用支付宝初始化账号购物车的交易
 func (d *Dao) InitTransaction() (string, error) {
-	params := Param.Params{}
-	params.SetInt64("gidShoppingCart", -1)
-	params.SetInt64("gidReplayOfTransID", -1)
-	params.SetInt64("bUseAccountCart", 1)
-	params.SetString("PaymentMethod", "alipay")
-	params.SetInt64("abortPendingTransactions", 0)
-	params.SetInt64("bHasCardInfo", 0)
-	params.SetString("CardNumber", "")
-	params.SetString("CardExpirationYear", "")
-	params.SetString("CardExpirationMonth", "")
-	params.SetString("FirstName", "")
-	params.SetString("LastName", "")
-	params.SetString("Address", "")
-	params.SetString("AddressTwo", "")
-	params.SetString("Country", "CN")
-	params.SetString("City", "")
-	params.SetString("State", "")
-	params.SetString("PostalCode", "")
-	params.SetString("Phone", "")
-	params.SetString("ShippingFirstName", "")
-	params.SetString("ShippingLastName", "")
-	params.SetString("ShippingAddress", "")
-	params.SetString("ShippingAddressTwo", "")
-	params.SetString("ShippingCountry", "CN")
-	params.SetString("ShippingCity", "")
-	params.SetString("ShippingState", "")
-	params.SetString("ShippingPostalCode", "")
-	params.SetString("ShippingPhone", "")
-	params.SetInt64("bIsGift", 0)
-	params.SetInt64("GifteeAccountID", 0)
-	params.SetString("GifteeEmail", "")
-	params.SetString("GifteeName", "")
-	params.SetString("GiftMessage", "")
-	params.SetString("Sentiment", "")
-	params.SetString("Signature", "")
-	params.SetInt64("ScheduledSendOnDate", 0)
-	params.SetString("BankAccount", "")
-	params.SetString("BankCode", "")
-	params.SetString("BankIBAN", "")
-	params.SetString("BankBIC", "")
-	params.SetString("TPBankID", "")
-	params.SetString("BankAccountID", "")
-	params.SetInt64("bSaveBillingAddress", 1)
-	params.SetString("gidPaymentID", "")
-	params.SetInt64("bUseRemainingSteamAccount", 1)
-	params.SetInt64("bPreAuthOnly", 0)
+	return d.InitTransactionWithOptions(&Model.TransactionOptions{
+		PaymentMethod:      Model.PaymentMethodAlipay,
+		SaveBillingAddress: true,
+	})
+}
+
+// InitConcurrentTransaction 使用 Steam 钱包初始化账号购物车的交易
+func (d *Dao) InitConcurrentTransaction() (string, error) {
+	return d.InitTransactionWithOptions(&Model.TransactionOptions{
+		PaymentMethod:      Model.PaymentMethodSteamWallet,
+		SaveBillingAddress: true,
+	})
+}
 
+// InitTransactionWithOptions 按选项初始化账号购物车的交易，返回交易ID
+// opts 为空时使用支付宝，账单地区默认为账号所在地区
+func (d *Dao) InitTransactionWithOptions(opts *Model.TransactionOptions) (string, error) {
 	if d.GetLoginCookies()["checkout.steampowered.com"] == nil {
 		return "", errors.New("checkout.steampowered.com cookie not found")
 	}
-	sessionId := d.GetLoginCookies()["checkout.steampowered.com"].SessionId
-	params.SetString("sessionid", sessionId)
+
+	transactionParams := d.initTransactionParams(opts)
+	transactionParams.SessionID = d.GetLoginCookies()["checkout.steampowered.com"].SessionId
+
+	params, err := Param.EncodeForm(transactionParams)
+	if err != nil {
+		return "", err
+	}
 
 	req, err := d.Request(http.MethodPost, Constants.InitTransaction, strings.NewReader(params.Encode()))
 	if err != nil {
@@ -236,96 +213,57 @@ func (d *Dao) InitTransaction() (string, error) {
 	return response.TransID, nil
 }
 
-func (d *Dao) InitConcurrentTransaction() (string, error) {
-	params := Param.Params{}
-	params.SetInt64("gidShoppingCart", -1)
-	params.SetInt64("gidReplayOfTransID", -1)
-	params.SetInt64("bUseAccountCart", 1)
-	params.SetString("PaymentMethod", "steamaccount")
-	params.SetInt64("abortPendingTransactions", 0)
-	params.SetInt64("bHasCardInfo", 0)
-	params.SetString("CardNumber", "")
-	params.SetString("CardExpirationYear", "")
-	params.SetString("CardExpirationMonth", "")
-	params.SetString("FirstName", "")
-	params.SetString("LastName", "")
-	params.SetString("Address", "")
-	params.SetString("AddressTwo", "")
-	params.SetString("Country", "CN")
-	params.SetString("City", "")
-	params.SetString("State", "")
-	params.SetString("PostalCode", "")
-	params.SetString("Phone", "")
-	params.SetString("ShippingFirstName", "")
-	params.SetString("ShippingLastName", "")
-	params.SetString("ShippingAddress", "")
-	params.SetString("ShippingAddressTwo", "")
-	params.SetString("ShippingCountry", "CN")
-	params.SetString("ShippingCity", "")
-	params.SetString("ShippingState", "")
-	params.SetString("ShippingPostalCode", "")
-	params.SetString("ShippingPhone", "")
-	params.SetInt64("bIsGift", 0)
-	params.SetInt64("GifteeAccountID", 0)
-	params.SetString("GifteeEmail", "")
-	params.SetString("GifteeName", "")
-	params.SetString("GiftMessage", "")
-	params.SetString("Sentiment", "")
-	params.SetString("Signature", "")
-	params.SetInt64("ScheduledSendOnDate", 0)
-	params.SetString("BankAccount", "")
-	params.SetString("BankCode", "")
-	params.SetString("BankIBAN", "")
-	params.SetString("BankBIC", "")
-	params.SetString("TPBankID", "")
-	params.SetString("BankAccountID", "")
-	params.SetInt64("bSaveBillingAddress", 1)
-	params.SetString("gidPaymentID", "")
-	params.SetInt64("bUseRemainingSteamAccount", 1)
-	params.SetInt64("bPreAuthOnly", 0)
-
-	if d.GetLoginCookies()["checkout.steampowered.com"] == nil {
-		return "", errors.New("checkout.steampowered.com cookie not found")
-	}
-	sessionId := d.GetLoginCookies()["checkout.steampowered.com"].SessionId
-	params.SetString("sessionid", sessionId)
-
-	req, err := d.Request(http.MethodPost, Constants.InitTransaction, strings.NewReader(params.Encode()))
-	if err != nil {
-		return "", err
-	}
-
-	req.Header.Set("origin", "https://checkout.steampowered.com")
-	req.Header.Set("referer", "https://checkout.steampowered.com/checkout/?accountcart=1")
-
-	resp, err := d.RetryRequest(Constants.Tries, req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-
-	if resp.StatusCode != 200 {
-		fmt.Println(string(body))
-		return "", fmt.Errorf("初始化交易失败,返回状态码: %d", resp.StatusCode)
+// initTransactionParams 将交易选项转换为 inittransaction 的表单参数，不包含 sessionid
+func (d *Dao) initTransactionParams(opts *Model.TransactionOptions) *InitTransactionParams {
+	options := Model.TransactionOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.PaymentMethod == "" {
+		options.PaymentMethod = Model.PaymentMethodAlipay
+	}
+
+	country := strings.ToUpper(options.BillingCountry)
+	if country == "" {
+		country = d.GetCountryCode()
+	}
+	if country == "" {
+		country = "CN"
+	}
+
+	params := &InitTransactionParams{
+		GidShoppingCart:          -1,
+		GidReplayOfTransID:       -1,
+		UseAccountCart:           1,
+		PaymentMethod:            string(options.PaymentMethod),
+		AbortPendingTransactions: boolToInt64(options.AbortPendingTransactions),
+		Country:                  country,
+		ShippingCountry:          country,
+		SaveBillingAddress:       boolToInt64(options.SaveBillingAddress),
+		UseRemainingSteamAccount: 1,
+	}
+
+	if options.IsGift {
+		params.IsGift = 1
+		params.GifteeAccountID = int64(options.GifteeAccountID)
+		params.GifteeEmail = options.GifteeEmail
+		params.GifteeName = options.GifteeName
+		params.GiftMessage = options.GiftMessage
+		params.Sentiment = options.Sentiment
+		params.Signature = options.Signature
+		if !options.ScheduledSendOn.IsZero() {
+			params.ScheduledSendOnDate = options.ScheduledSendOn.Unix()
+		}
 	}
 
-	var response InitTransactionResponse
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return "", fmt.Errorf("解析初始化交易响应失败: %w", err)
-	}
+	return params
+}
 
-	if response.Success != 1 {
-		fmt.Println(string(body))
-		return "", errors.New(Errors.GetCheckoutError(response.PurchaseResultDetail))
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
 	}
-
-	return response.TransID, nil
+	return 0
 }
 
 func (d *Dao) FinalizeTransaction(transactionID string) error {
@@ -540,35 +478,44 @@ func (d *Dao) TestGetPayLinkAgain() (string, error) {
 }
 
 func (d *Dao) AccessCheckoutURL(transactionID string) (string, error) {
+	formData, err := d.fetchPaymentForm(transactionID)
+	if err != nil {
+		return "", err
+	}
+
+	return d.resolvePaymentRedirect(formData)
+}
+
+// fetchPaymentForm 获取交易外部支付页面中的支付表单
+func (d *Dao) fetchPaymentForm(transactionID string) (*PaymentFormData, error) {
 	params := Param.Params{}
 	params.SetString("transid", transactionID)
 
 	req, err := d.Request(http.MethodGet, Constants.ExternallLink+"?"+params.ToUrl(), nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	resp, err := d.RetryRequest(Constants.Tries, req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// 解析HTML表单
-	formData, err := ParsePaymentForm(string(body))
-	if err != nil {
-		return "", err
-	}
+	return ParsePaymentForm(string(body))
+}
 
+// resolvePaymentRedirect 提交支付表单，从返回的页面中提取支付跳转链接
+func (d *Dao) resolvePaymentRedirect(formData *PaymentFormData) (string, error) {
 	paramsForPayLink := Param.Params{}
 
 	for key, value := range formData.Fields {
-		fmt.Printf("  %s: %s\n", key, value)
 		paramsForPayLink.SetString(key, value)
 	}
 
@@ -658,6 +605,7 @@ func ExtractRedirectURL(htmlContent string) (string, error) {
 // PaymentFormData 存储支付表单的数据
 type PaymentFormData struct {
 	Action string            // 表单的action URL
+	Method string            // 表单的提交方式，未指定时为 GET
 	Fields map[string]string // 表单中的所有隐藏字段
 }
 
@@ -676,13 +624,18 @@ func ParsePaymentForm(htmlContent string) (*PaymentFormData, error) {
 	var findForm func(*html.Node) bool
 	findForm = func(n *html.Node) bool {
 		if n.Type == html.ElementNode && n.Data == "form" {
-			// 获取form的action属性
+			// 获取form的action和method属性
 			for _, attr := range n.Attr {
-				if attr.Key == "action" {
+				switch attr.Key {
+				case "action":
 					formData.Action = attr.Val
-					break
+				case "method":
+					formData.Method = strings.ToUpper(attr.Val)
 				}
 			}
+			if formData.Method == "" {
+				formData.Method = http.MethodGet
+			}
 
 			// 查找所有input字段
 			var findInputs func(*html.Node)
@@ -853,6 +806,20 @@ func (d *Dao) UnsendGift(giftId string) error {
 }
 
 func (d *Dao) TransactionStatus(transId string, count int) error {
+	result, err := d.GetTransactionStatus(transId, count)
+	if err != nil {
+		return err
+	}
+
+	if result.Success != 1 {
+		return fmt.Errorf("订单未完成,返回Success: %d", result.Success)
+	}
+
+	return nil
+}
+
+// GetTransactionStatus 查询交易状态，Success 为 1 时交易已完成，PurchaseReceipt 为收据
+func (d *Dao) GetTransactionStatus(transId string, count int) (*Model.TransactionStatusResponse, error) {
 	var result Model.TransactionStatusResponse
 
 	params := Param.Params{}
@@ -861,32 +828,28 @@ func (d *Dao) TransactionStatus(transId string, count int) error {
 
 	req, err := d.Request(http.MethodGet, Constants.TransactionStatus+"?"+params.ToUrl(), nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	resp, err := d.RetryRequest(Constants.Tries, req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if resp.StatusCode != 200 {
-		return errors.New("获取交易状态失败,返回状态码: " + strconv.Itoa(resp.StatusCode))
+		return nil, errors.New("获取交易状态失败,返回状态码: " + strconv.Itoa(resp.StatusCode))
 	}
 
 	err = json.Unmarshal(body, &result)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if result.Success != 1 {
-		return fmt.Errorf("订单未完成,返回Success: %d", result.Success)
-	}
-
-	return nil
+	return &result, nil
 }
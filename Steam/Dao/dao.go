@@ -14,6 +14,7 @@ import (
 
 	"github.com/JovanniChen/SteamDB/Steam/Constants"
 	"github.com/JovanniChen/SteamDB/Steam/Errors"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
 )
 
 type globalConfig struct {
@@ -30,6 +31,10 @@ type Dao struct {
 	credentials     *Credentials  // 用户凭据信息，包含登录状态和认证信息
 	global          *globalConfig // 全局配置信息
 	requestCallback func()        // HTTP请求成功后的回调函数，用于外部监控请求
+
+	feeMu         sync.RWMutex      // 保护钱包信息和发行商手续费配置
+	walletInfo    *Model.WalletInfo // 最近一次获取到的钱包信息，用于计算市场手续费
+	publisherFees map[int]float64   // 按游戏覆盖的发行商手续费比例
 }
 
 // Request 创建包含认证信息的HTTP请求
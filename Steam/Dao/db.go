@@ -2,8 +2,12 @@ package Dao
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/JovanniChen/SteamDB/Steam/Model"
 	_ "github.com/mattn/go-sqlite3"
@@ -50,6 +54,181 @@ func initDB(path string) error {
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_game_id ON game_update_events(game_id);
+
+	CREATE TABLE IF NOT EXISTS store_prices (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		item_type INTEGER NOT NULL,
+		item_id INTEGER NOT NULL,
+		country_code TEXT NOT NULL,
+		final_price INTEGER NOT NULL,
+		original_price INTEGER NOT NULL,
+		currency INTEGER NOT NULL,
+		discount_pct INTEGER NOT NULL,
+		observed_at INTEGER NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_store_prices_item ON store_prices(item_type, item_id, country_code, observed_at);
+
+	CREATE TABLE IF NOT EXISTS market_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		steam_id INTEGER NOT NULL,
+		row_id TEXT NOT NULL,
+		listing_id TEXT NOT NULL,
+		event_type TEXT NOT NULL,
+		app_id INTEGER NOT NULL,
+		context_id TEXT NOT NULL,
+		asset_id TEXT NOT NULL,
+		item_name TEXT NOT NULL,
+		game_name TEXT NOT NULL,
+		price INTEGER NOT NULL,
+		currency INTEGER NOT NULL,
+		price_text TEXT NOT NULL,
+		counterparty_name TEXT NOT NULL,
+		counterparty_url TEXT NOT NULL,
+		acted_on INTEGER NOT NULL,
+		listed_on INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(steam_id, row_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_market_history_steam_id ON market_history(steam_id, acted_on);
+
+	CREATE TABLE IF NOT EXISTS sync_state (
+		steam_id INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		value TEXT NOT NULL,
+		updated_at INTEGER NOT NULL,
+		PRIMARY KEY(steam_id, name)
+	);
+
+	CREATE TABLE IF NOT EXISTS inventory_snapshots (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		steam_id INTEGER NOT NULL,
+		app_id INTEGER NOT NULL,
+		context_id INTEGER NOT NULL,
+		total_count INTEGER NOT NULL,
+		content_hash TEXT NOT NULL,
+		fetched_at INTEGER NOT NULL,
+		UNIQUE(steam_id, app_id, context_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS inventory_snapshot_items (
+		steam_id INTEGER NOT NULL,
+		app_id INTEGER NOT NULL,
+		context_id INTEGER NOT NULL,
+		asset_id TEXT NOT NULL,
+		class_id TEXT NOT NULL,
+		instance_id TEXT NOT NULL,
+		amount INTEGER NOT NULL,
+		description TEXT NOT NULL,
+		PRIMARY KEY(steam_id, app_id, context_id, asset_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS trade_offers (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		steam_id INTEGER NOT NULL,
+		trade_offer_id TEXT NOT NULL,
+		partner_steam_id INTEGER NOT NULL,
+		is_our_offer INTEGER NOT NULL,
+		state INTEGER NOT NULL,
+		trade_id TEXT NOT NULL DEFAULT '',
+		time_created INTEGER NOT NULL,
+		time_updated INTEGER NOT NULL,
+		data TEXT NOT NULL,
+		UNIQUE(steam_id, trade_offer_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_trade_offers_updated ON trade_offers(steam_id, time_updated);
+
+	CREATE TABLE IF NOT EXISTS trade_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		steam_id INTEGER NOT NULL,
+		trade_id TEXT NOT NULL,
+		steam_id_other INTEGER NOT NULL,
+		time_init INTEGER NOT NULL,
+		status INTEGER NOT NULL,
+		data TEXT NOT NULL,
+		UNIQUE(steam_id, trade_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS trade_history_assets (
+		steam_id INTEGER NOT NULL,
+		trade_id TEXT NOT NULL,
+		steam_id_other INTEGER NOT NULL,
+		time_init INTEGER NOT NULL,
+		direction TEXT NOT NULL,
+		app_id INTEGER NOT NULL,
+		context_id TEXT NOT NULL,
+		asset_id TEXT NOT NULL,
+		new_context_id TEXT NOT NULL,
+		new_asset_id TEXT NOT NULL,
+		PRIMARY KEY(steam_id, trade_id, direction, app_id, asset_id)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_trade_history_new_asset ON trade_history_assets(app_id, new_asset_id);
+
+	CREATE TABLE IF NOT EXISTS reprice_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		steam_id INTEGER NOT NULL,
+		listing_id TEXT NOT NULL,
+		app_id INTEGER NOT NULL,
+		market_hash_name TEXT NOT NULL,
+		old_price INTEGER NOT NULL,
+		new_price INTEGER NOT NULL,
+		currency INTEGER NOT NULL,
+		relisted_at INTEGER NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_reprice_log_name ON reprice_log(steam_id, app_id, market_hash_name, relisted_at);
+
+	CREATE TABLE IF NOT EXISTS purchases (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		steam_id INTEGER NOT NULL,
+		state TEXT NOT NULL,
+		payment_method TEXT NOT NULL,
+		items TEXT NOT NULL,
+		trans_id TEXT NOT NULL,
+		total INTEGER NOT NULL,
+		currency INTEGER NOT NULL,
+		payment_url TEXT NOT NULL,
+		error TEXT NOT NULL,
+		receipt TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_purchases_state ON purchases(steam_id, state);
+
+	CREATE TABLE IF NOT EXISTS managed_buy_orders (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		steam_id INTEGER NOT NULL,
+		buy_order_id TEXT NOT NULL,
+		app_id INTEGER NOT NULL,
+		market_hash_name TEXT NOT NULL,
+		price INTEGER NOT NULL,
+		currency INTEGER NOT NULL,
+		quantity INTEGER NOT NULL,
+		quantity_remaining INTEGER NOT NULL,
+		state TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL,
+		UNIQUE(steam_id, buy_order_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS buy_order_fills (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		steam_id INTEGER NOT NULL,
+		buy_order_id TEXT NOT NULL,
+		listing_id TEXT NOT NULL,
+		app_id INTEGER NOT NULL,
+		context_id TEXT NOT NULL,
+		asset_id TEXT NOT NULL,
+		market_hash_name TEXT NOT NULL,
+		price INTEGER NOT NULL,
+		currency INTEGER NOT NULL,
+		filled_at INTEGER NOT NULL,
+		UNIQUE(steam_id, buy_order_id, listing_id)
+	);
 	`
 
 	if _, err := db.Exec(createTableSQL); err != nil {
@@ -156,3 +335,939 @@ func CheckAndSaveUpdateEvent(gameID int, newEvent *Model.UpdateEventInfo) (needU
 	// UniqueID 一致，说明没有新更新
 	return false, nil
 }
+
+// GetLatestStorePrice 获取物品在某个地区最近一次记录的价格，没有记录时返回 nil
+func GetLatestStorePrice(item Model.StoreItemID, countryCode string) (*Model.StorePriceRecord, error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return nil, err
+	}
+
+	record := &Model.StorePriceRecord{Item: item, CountryCode: countryCode}
+	var observedAt int64
+	err := db.QueryRow(`
+		SELECT final_price, original_price, currency, discount_pct, observed_at
+		FROM store_prices
+		WHERE item_type = ? AND item_id = ? AND country_code = ?
+		ORDER BY observed_at DESC, id DESC
+		LIMIT 1
+	`, int(item.Type()), item.ID(), countryCode).Scan(
+		&record.Final.Amount,
+		&record.Original.Amount,
+		&record.Final.Currency,
+		&record.DiscountPct,
+		&observedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询价格记录失败: %w", err)
+	}
+
+	record.Original.Currency = record.Final.Currency
+	record.ObservedAt = time.Unix(observedAt, 0)
+	return record, nil
+}
+
+// GetLowestStorePrice 获取物品在某个地区以指定货币记录的最低价，没有记录时 found 为 false
+// 限时免费领取（现价为 0 而原价大于 0）的记录不计入最低价
+func GetLowestStorePrice(item Model.StoreItemID, countryCode string, currency Model.Currency) (lowest Model.Money, found bool, err error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return Model.Money{}, false, err
+	}
+
+	err = db.QueryRow(`
+		SELECT final_price, currency
+		FROM store_prices
+		WHERE item_type = ? AND item_id = ? AND country_code = ? AND currency = ?
+			AND NOT (final_price = 0 AND original_price > 0)
+		ORDER BY final_price ASC, observed_at ASC
+		LIMIT 1
+	`, int(item.Type()), item.ID(), countryCode, int(currency)).Scan(&lowest.Amount, &lowest.Currency)
+	if err == sql.ErrNoRows {
+		return Model.Money{}, false, nil
+	}
+	if err != nil {
+		return Model.Money{}, false, fmt.Errorf("查询历史最低价失败: %w", err)
+	}
+
+	return lowest, true, nil
+}
+
+// SaveStorePrice 保存一次价格记录
+func SaveStorePrice(record *Model.StorePriceRecord) error {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return err
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO store_prices (item_type, item_id, country_code, final_price, original_price, currency, discount_pct, observed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, int(record.Item.Type()), record.Item.ID(), record.CountryCode, record.Final.Amount, record.Original.Amount,
+		int(record.Final.Currency), record.DiscountPct, record.ObservedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("保存价格记录失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetStorePriceHistory 获取物品在某个地区的价格记录，按时间倒序
+func GetStorePriceHistory(item Model.StoreItemID, countryCode string) ([]Model.StorePriceRecord, error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT final_price, original_price, currency, discount_pct, observed_at
+		FROM store_prices
+		WHERE item_type = ? AND item_id = ? AND country_code = ?
+		ORDER BY observed_at DESC, id DESC
+	`, int(item.Type()), item.ID(), countryCode)
+	if err != nil {
+		return nil, fmt.Errorf("查询价格记录失败: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]Model.StorePriceRecord, 0)
+	for rows.Next() {
+		record := Model.StorePriceRecord{Item: item, CountryCode: countryCode}
+		var observedAt int64
+		if err := rows.Scan(&record.Final.Amount, &record.Original.Amount, &record.Final.Currency, &record.DiscountPct, &observedAt); err != nil {
+			return nil, fmt.Errorf("读取价格记录失败: %w", err)
+		}
+		record.Original.Currency = record.Final.Currency
+		record.ObservedAt = time.Unix(observedAt, 0)
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// SaveMarketHistoryEvent 保存一条市场交易历史
+// 返回值：inserted - 是否为新记录（已存在的记录只更新内容）
+func SaveMarketHistoryEvent(steamID uint64, event *Model.MarketHistoryEvent) (inserted bool, err error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return false, err
+	}
+
+	var exists int
+	if err := db.QueryRow(`SELECT COUNT(1) FROM market_history WHERE steam_id = ? AND row_id = ?`, steamID, event.RowID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("查询交易历史失败: %w", err)
+	}
+
+	upsertSQL := `
+		INSERT INTO market_history (steam_id, row_id, listing_id, event_type, app_id, context_id, asset_id,
+			item_name, game_name, price, currency, price_text, counterparty_name, counterparty_url, acted_on, listed_on)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(steam_id, row_id) DO UPDATE SET
+			listing_id = excluded.listing_id,
+			event_type = excluded.event_type,
+			app_id = excluded.app_id,
+			context_id = excluded.context_id,
+			asset_id = excluded.asset_id,
+			item_name = excluded.item_name,
+			game_name = excluded.game_name,
+			price = excluded.price,
+			currency = excluded.currency,
+			price_text = excluded.price_text,
+			counterparty_name = excluded.counterparty_name,
+			counterparty_url = excluded.counterparty_url
+	`
+
+	_, err = db.Exec(upsertSQL, steamID, event.RowID, event.ListingID, string(event.Type), event.AppID,
+		event.ContextID, event.AssetID, event.ItemName, event.GameName, event.Price.Amount, int(event.Price.Currency), event.PriceText,
+		event.CounterpartyName, event.CounterpartyURL, unixOrZero(event.ActedOn), unixOrZero(event.ListedOn))
+	if err != nil {
+		return false, fmt.Errorf("保存交易历史失败: %w", err)
+	}
+
+	return exists == 0, nil
+}
+
+// GetSyncState 获取账号的同步状态（如增量同步的游标），不存在时返回空字符串
+func GetSyncState(steamID uint64, name string) (string, error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return "", err
+	}
+
+	var value string
+	err := db.QueryRow(`SELECT value FROM sync_state WHERE steam_id = ? AND name = ?`, steamID, name).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("查询同步状态失败: %w", err)
+	}
+
+	return value, nil
+}
+
+// SaveSyncState 保存账号的同步状态
+func SaveSyncState(steamID uint64, name string, value string) error {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return err
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO sync_state (steam_id, name, value, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(steam_id, name) DO UPDATE SET
+			value = excluded.value,
+			updated_at = excluded.updated_at
+	`, steamID, name, value, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("保存同步状态失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetMarketHistoryEvents 查询数据库中指定账号的市场交易历史（按时间从新到旧）
+func GetMarketHistoryEvents(steamID uint64) ([]Model.MarketHistoryEvent, error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return nil, err
+	}
+
+	return queryMarketHistoryEvents(`steam_id = ?`, steamID)
+}
+
+// queryMarketHistoryEvents 按条件查询交易历史（按时间从新到旧）
+func queryMarketHistoryEvents(where string, args ...any) ([]Model.MarketHistoryEvent, error) {
+	query := `
+		SELECT row_id, listing_id, event_type, app_id, context_id, asset_id, item_name, game_name,
+			price, currency, price_text, counterparty_name, counterparty_url, acted_on, listed_on
+		FROM market_history
+		WHERE ` + where + `
+		ORDER BY acted_on DESC, id ASC
+	`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询交易历史失败: %w", err)
+	}
+	defer rows.Close()
+
+	events := make([]Model.MarketHistoryEvent, 0)
+	for rows.Next() {
+		var event Model.MarketHistoryEvent
+		var eventType string
+		var actedOn, listedOn int64
+		if err := rows.Scan(&event.RowID, &event.ListingID, &eventType, &event.AppID, &event.ContextID,
+			&event.AssetID, &event.ItemName, &event.GameName, &event.Price.Amount, &event.Price.Currency, &event.PriceText,
+			&event.CounterpartyName, &event.CounterpartyURL, &actedOn, &listedOn); err != nil {
+			return nil, fmt.Errorf("读取交易历史失败: %w", err)
+		}
+		event.Type = Model.MarketHistoryEventType(eventType)
+		event.ActedOn = timeOrZero(actedOn)
+		event.ListedOn = timeOrZero(listedOn)
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// unixOrZero 将时间转换为Unix时间戳，零值返回0
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// timeOrZero 将Unix时间戳转换为时间，0返回零值
+func timeOrZero(ts int64) time.Time {
+	if ts == 0 {
+		return time.Time{}
+	}
+	return time.Unix(ts, 0)
+}
+
+// GetInventorySnapshot 获取指定账号/游戏/上下文的最新库存快照，没有记录时返回 nil
+// withItems 为 false 时只读取快照摘要信息，不加载物品列表
+func GetInventorySnapshot(steamID uint64, appID int, contextID int, withItems bool) (*Model.InventorySnapshot, error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return nil, err
+	}
+
+	snapshot := &Model.InventorySnapshot{
+		SteamID:   steamID,
+		AppID:     appID,
+		ContextID: contextID,
+	}
+
+	var fetchedAt int64
+	err := db.QueryRow(`
+		SELECT total_count, content_hash, fetched_at
+		FROM inventory_snapshots
+		WHERE steam_id = ? AND app_id = ? AND context_id = ?
+	`, steamID, appID, contextID).Scan(&snapshot.TotalCount, &snapshot.Hash, &fetchedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil // 没有记录，返回 nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("查询库存快照失败: %w", err)
+	}
+	snapshot.FetchedAt = time.Unix(fetchedAt, 0)
+
+	if !withItems {
+		return snapshot, nil
+	}
+
+	rows, err := db.Query(`
+		SELECT asset_id, class_id, instance_id, amount, description
+		FROM inventory_snapshot_items
+		WHERE steam_id = ? AND app_id = ? AND context_id = ?
+	`, steamID, appID, contextID)
+	if err != nil {
+		return nil, fmt.Errorf("查询库存快照物品失败: %w", err)
+	}
+	defer rows.Close()
+
+	snapshot.Items = make([]Model.InventoryItem, 0)
+	for rows.Next() {
+		item := Model.InventoryItem{
+			AppID:     appID,
+			ContextID: strconv.Itoa(contextID),
+		}
+		var description string
+		if err := rows.Scan(&item.AssetID, &item.ClassID, &item.InstanceID, &item.Amount, &description); err != nil {
+			return nil, fmt.Errorf("读取库存快照物品失败: %w", err)
+		}
+		if err := json.Unmarshal([]byte(description), &item.Description); err != nil {
+			return nil, fmt.Errorf("解析库存快照物品描述失败: %w", err)
+		}
+		snapshot.Items = append(snapshot.Items, item)
+	}
+
+	return snapshot, rows.Err()
+}
+
+// SaveInventorySnapshot 保存库存快照，替换该账号/游戏/上下文之前的快照
+func SaveInventorySnapshot(snapshot *Model.InventorySnapshot) error {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT OR REPLACE INTO inventory_snapshots (steam_id, app_id, context_id, total_count, content_hash, fetched_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, snapshot.SteamID, snapshot.AppID, snapshot.ContextID, snapshot.TotalCount, snapshot.Hash, snapshot.FetchedAt.Unix()); err != nil {
+		return fmt.Errorf("保存库存快照失败: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		DELETE FROM inventory_snapshot_items WHERE steam_id = ? AND app_id = ? AND context_id = ?
+	`, snapshot.SteamID, snapshot.AppID, snapshot.ContextID); err != nil {
+		return fmt.Errorf("清理库存快照物品失败: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO inventory_snapshot_items (steam_id, app_id, context_id, asset_id, class_id, instance_id, amount, description)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("保存库存快照物品失败: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, item := range snapshot.Items {
+		description, err := json.Marshal(item.Description)
+		if err != nil {
+			return fmt.Errorf("序列化物品描述失败: %w", err)
+		}
+		if _, err := stmt.Exec(snapshot.SteamID, snapshot.AppID, snapshot.ContextID, item.AssetID,
+			item.ClassID, item.InstanceID, item.Amount, string(description)); err != nil {
+			return fmt.Errorf("保存库存快照物品失败: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// TouchInventorySnapshot 库存未变化时只更新快照时间
+func TouchInventorySnapshot(steamID uint64, appID int, contextID int, fetchedAt time.Time) error {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return err
+	}
+
+	_, err := db.Exec(`
+		UPDATE inventory_snapshots SET fetched_at = ? WHERE steam_id = ? AND app_id = ? AND context_id = ?
+	`, fetchedAt.Unix(), steamID, appID, contextID)
+	if err != nil {
+		return fmt.Errorf("更新库存快照时间失败: %w", err)
+	}
+	return nil
+}
+
+// GetTradeOfferState 查询数据库中记录的报价状态，没有记录时 found 为 false
+func GetTradeOfferState(steamID uint64, tradeOfferID string) (state Model.TradeOfferState, found bool, err error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return 0, false, err
+	}
+
+	err = db.QueryRow(`SELECT state FROM trade_offers WHERE steam_id = ? AND trade_offer_id = ?`, steamID, tradeOfferID).Scan(&state)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("查询报价状态失败: %w", err)
+	}
+
+	return state, true, nil
+}
+
+// SaveTradeOffer 保存或更新报价
+func SaveTradeOffer(steamID uint64, offer *Model.TradeOffer) error {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(offer)
+	if err != nil {
+		return fmt.Errorf("序列化报价失败: %w", err)
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO trade_offers (steam_id, trade_offer_id, partner_steam_id, is_our_offer, state, trade_id, time_created, time_updated, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(steam_id, trade_offer_id) DO UPDATE SET
+			state = excluded.state,
+			trade_id = excluded.trade_id,
+			time_updated = excluded.time_updated,
+			data = excluded.data
+	`, steamID, offer.TradeOfferID, offer.PartnerSteamID(), offer.IsOurOffer, int(offer.State), offer.TradeID,
+		offer.TimeCreated, offer.TimeUpdated, string(data))
+	if err != nil {
+		return fmt.Errorf("保存报价失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestTradeOfferUpdate 获取数据库中最近一次报价更新时间，没有记录时返回零值
+func GetLatestTradeOfferUpdate(steamID uint64) (time.Time, error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return time.Time{}, err
+	}
+
+	var latest sql.NullInt64
+	if err := db.QueryRow(`SELECT MAX(time_updated) FROM trade_offers WHERE steam_id = ?`, steamID).Scan(&latest); err != nil {
+		return time.Time{}, fmt.Errorf("查询报价更新时间失败: %w", err)
+	}
+	if !latest.Valid {
+		return time.Time{}, nil
+	}
+
+	return time.Unix(latest.Int64, 0), nil
+}
+
+// GetStoredTradeOffers 获取数据库中保存的报价（按更新时间从新到旧）
+func GetStoredTradeOffers(steamID uint64) ([]Model.TradeOffer, error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT data FROM trade_offers WHERE steam_id = ? ORDER BY time_updated DESC, id DESC`, steamID)
+	if err != nil {
+		return nil, fmt.Errorf("查询报价失败: %w", err)
+	}
+	defer rows.Close()
+
+	offers := make([]Model.TradeOffer, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("读取报价失败: %w", err)
+		}
+		var offer Model.TradeOffer
+		if err := json.Unmarshal([]byte(data), &offer); err != nil {
+			return nil, fmt.Errorf("解析报价失败: %w", err)
+		}
+		offers = append(offers, offer)
+	}
+
+	return offers, rows.Err()
+}
+
+// SaveTradeHistoryTrade 保存或更新一笔交易及其物品，返回是否为新记录
+func SaveTradeHistoryTrade(steamID uint64, trade *Model.TradeHistoryTrade) (inserted bool, err error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return false, err
+	}
+
+	var exists int
+	if err := db.QueryRow(`SELECT COUNT(1) FROM trade_history WHERE steam_id = ? AND trade_id = ?`, steamID, trade.TradeID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("查询交易历史失败: %w", err)
+	}
+
+	data, err := json.Marshal(trade)
+	if err != nil {
+		return false, fmt.Errorf("序列化交易历史失败: %w", err)
+	}
+	steamIDOther, _ := strconv.ParseUint(trade.SteamIDOther, 10, 64)
+
+	tx, err := db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("开启事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO trade_history (steam_id, trade_id, steam_id_other, time_init, status, data)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(steam_id, trade_id) DO UPDATE SET
+			status = excluded.status,
+			data = excluded.data
+	`, steamID, trade.TradeID, steamIDOther, trade.TimeInit, int(trade.Status), string(data))
+	if err != nil {
+		return false, fmt.Errorf("保存交易历史失败: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM trade_history_assets WHERE steam_id = ? AND trade_id = ?`, steamID, trade.TradeID); err != nil {
+		return false, fmt.Errorf("清理交易物品失败: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT OR REPLACE INTO trade_history_assets (steam_id, trade_id, steam_id_other, time_init, direction,
+			app_id, context_id, asset_id, new_context_id, new_asset_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return false, fmt.Errorf("准备保存交易物品失败: %w", err)
+	}
+	defer stmt.Close()
+
+	for direction, assets := range map[string][]Model.TradeHistoryAsset{"given": trade.AssetsGiven, "received": trade.AssetsReceived} {
+		for _, asset := range assets {
+			if _, err := stmt.Exec(steamID, trade.TradeID, steamIDOther, trade.TimeInit, direction,
+				asset.AppID, asset.ContextID, asset.AssetID, asset.NewContextID, asset.NewAssetID); err != nil {
+				return false, fmt.Errorf("保存交易物品失败: %w", err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("提交事务失败: %w", err)
+	}
+
+	return exists == 0, nil
+}
+
+// GetOldestPendingTradeTime 获取数据库中最早一笔未结束交易（如暂挂中）的发起时间
+func GetOldestPendingTradeTime(steamID uint64) (timeInit int64, found bool, err error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return 0, false, err
+	}
+
+	statuses := Model.PendingTradeStatuses()
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(statuses)), ",")
+	args := []any{steamID}
+	for _, status := range statuses {
+		args = append(args, int(status))
+	}
+
+	var oldest sql.NullInt64
+	query := `SELECT MIN(time_init) FROM trade_history WHERE steam_id = ? AND status IN (` + placeholders + `)`
+	if err := db.QueryRow(query, args...).Scan(&oldest); err != nil {
+		return 0, false, fmt.Errorf("查询未结束交易失败: %w", err)
+	}
+	if !oldest.Valid {
+		return 0, false, nil
+	}
+
+	return oldest.Int64, true, nil
+}
+
+// GetStoredTradeHistory 获取数据库中保存的交易历史（按时间从新到旧）
+func GetStoredTradeHistory(steamID uint64) ([]Model.TradeHistoryTrade, error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT data FROM trade_history WHERE steam_id = ? ORDER BY time_init DESC, id DESC`, steamID)
+	if err != nil {
+		return nil, fmt.Errorf("查询交易历史失败: %w", err)
+	}
+	defer rows.Close()
+
+	trades := make([]Model.TradeHistoryTrade, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("读取交易历史失败: %w", err)
+		}
+		var trade Model.TradeHistoryTrade
+		if err := json.Unmarshal([]byte(data), &trade); err != nil {
+			return nil, fmt.Errorf("解析交易历史失败: %w", err)
+		}
+		trades = append(trades, trade)
+	}
+
+	return trades, rows.Err()
+}
+
+// GetAssetProvenance 根据数据库中所有账号的交易历史，沿 new_assetid 向前追溯物品经过的交易，
+// 并关联这些资产ID对应的市场交易历史
+func GetAssetProvenance(appID int, assetID string) (*Model.AssetProvenance, error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return nil, err
+	}
+
+	provenance := &Model.AssetProvenance{
+		AppID:    appID,
+		AssetID:  assetID,
+		AssetIDs: []string{assetID},
+		Trades:   make([]Model.AssetProvenanceHop, 0),
+	}
+
+	visited := map[string]bool{assetID: true}
+	for current := assetID; ; {
+		var hop Model.AssetProvenanceHop
+		var steamID, steamIDOther uint64
+		var direction string
+		var timeInit int64
+		err := db.QueryRow(`
+			SELECT steam_id, steam_id_other, trade_id, time_init, direction, asset_id, new_asset_id
+			FROM trade_history_assets
+			WHERE app_id = ? AND new_asset_id = ?
+			ORDER BY time_init DESC
+			LIMIT 1
+		`, appID, current).Scan(&steamID, &steamIDOther, &hop.TradeID, &timeInit, &direction, &hop.AssetID, &hop.NewAssetID)
+		if err == sql.ErrNoRows {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("查询物品交易记录失败: %w", err)
+		}
+
+		hop.TimeInit = time.Unix(timeInit, 0)
+		if direction == "given" {
+			hop.FromSteamID, hop.ToSteamID = steamID, steamIDOther
+		} else {
+			hop.FromSteamID, hop.ToSteamID = steamIDOther, steamID
+		}
+		provenance.Trades = append(provenance.Trades, hop)
+
+		if visited[hop.AssetID] {
+			break
+		}
+		visited[hop.AssetID] = true
+		provenance.AssetIDs = append(provenance.AssetIDs, hop.AssetID)
+		current = hop.AssetID
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(provenance.AssetIDs)), ",")
+	args := []any{appID}
+	for _, id := range provenance.AssetIDs {
+		args = append(args, id)
+	}
+	events, err := queryMarketHistoryEvents(`app_id = ? AND asset_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	provenance.MarketEvents = events
+
+	return provenance, nil
+}
+
+// SaveRepriceRecord 记录一次改价
+func SaveRepriceRecord(steamID uint64, decision *Model.RepriceDecision, relistedAt time.Time) error {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return err
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO reprice_log (steam_id, listing_id, app_id, market_hash_name, old_price, new_price, currency, relisted_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, steamID, decision.ListingID, decision.AppID, decision.MarketHashName, decision.OldPrice.Amount,
+		decision.NewPrice.Amount, int(decision.NewPrice.Currency), relistedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("保存改价记录失败: %w", err)
+	}
+
+	return nil
+}
+
+// CountRelistsSince 统计指定时间之后某物品的改价次数
+func CountRelistsSince(steamID uint64, appID int, marketHashName string, since time.Time) (int, error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return 0, err
+	}
+
+	var count int
+	err := db.QueryRow(`
+		SELECT COUNT(1) FROM reprice_log
+		WHERE steam_id = ? AND app_id = ? AND market_hash_name = ? AND relisted_at >= ?
+	`, steamID, appID, marketHashName, since.Unix()).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("查询改价记录失败: %w", err)
+	}
+
+	return count, nil
+}
+
+// SaveManagedBuyOrder 保存或更新受管理的订购单
+func SaveManagedBuyOrder(steamID uint64, order *Model.ManagedBuyOrder) error {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return err
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO managed_buy_orders (steam_id, buy_order_id, app_id, market_hash_name, price, currency,
+			quantity, quantity_remaining, state, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(steam_id, buy_order_id) DO UPDATE SET
+			quantity_remaining = excluded.quantity_remaining,
+			state = excluded.state,
+			updated_at = excluded.updated_at
+	`, steamID, order.BuyOrderID, order.AppID, order.MarketHashName, order.Price.Amount, int(order.Price.Currency),
+		order.Quantity, order.QuantityRemaining, string(order.State), order.CreatedAt.Unix(), order.UpdatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("保存订购单失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetManagedBuyOrders 获取受管理的订购单，activeOnly 为 true 时只返回进行中的订购单
+func GetManagedBuyOrders(steamID uint64, activeOnly bool) ([]Model.ManagedBuyOrder, error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT buy_order_id, app_id, market_hash_name, price, currency, quantity, quantity_remaining, state, created_at, updated_at
+		FROM managed_buy_orders
+		WHERE steam_id = ?
+	`
+	args := []any{steamID}
+	if activeOnly {
+		query += ` AND state = ?`
+		args = append(args, string(Model.ManagedBuyOrderActive))
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询订购单失败: %w", err)
+	}
+	defer rows.Close()
+
+	orders := make([]Model.ManagedBuyOrder, 0)
+	for rows.Next() {
+		var order Model.ManagedBuyOrder
+		var state string
+		var createdAt, updatedAt int64
+		if err := rows.Scan(&order.BuyOrderID, &order.AppID, &order.MarketHashName, &order.Price.Amount, &order.Price.Currency,
+			&order.Quantity, &order.QuantityRemaining, &state, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("读取订购单失败: %w", err)
+		}
+		order.State = Model.ManagedBuyOrderState(state)
+		order.CreatedAt = time.Unix(createdAt, 0)
+		order.UpdatedAt = time.Unix(updatedAt, 0)
+		orders = append(orders, order)
+	}
+
+	return orders, rows.Err()
+}
+
+// SaveBuyOrderFill 保存订购单成交记录，返回是否为新记录
+func SaveBuyOrderFill(steamID uint64, fill *Model.BuyOrderFill) (inserted bool, err error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return false, err
+	}
+
+	result, err := db.Exec(`
+		INSERT OR IGNORE INTO buy_order_fills (steam_id, buy_order_id, listing_id, app_id, context_id, asset_id,
+			market_hash_name, price, currency, filled_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, steamID, fill.BuyOrderID, fill.ListingID, fill.AppID, fill.ContextID, fill.AssetID, fill.MarketHashName,
+		fill.Price.Amount, int(fill.Price.Currency), fill.FilledAt.Unix())
+	if err != nil {
+		return false, fmt.Errorf("保存订购单成交记录失败: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// GetBuyOrderFills 获取订购单成交记录，buyOrderID 为空时返回该账号的全部记录
+func GetBuyOrderFills(steamID uint64, buyOrderID string) ([]Model.BuyOrderFill, error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT buy_order_id, listing_id, app_id, context_id, asset_id, market_hash_name, price, currency, filled_at
+		FROM buy_order_fills
+		WHERE steam_id = ?
+	`
+	args := []any{steamID}
+	if buyOrderID != "" {
+		query += ` AND buy_order_id = ?`
+		args = append(args, buyOrderID)
+	}
+	query += ` ORDER BY filled_at DESC, id DESC`
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询订购单成交记录失败: %w", err)
+	}
+	defer rows.Close()
+
+	fills := make([]Model.BuyOrderFill, 0)
+	for rows.Next() {
+		var fill Model.BuyOrderFill
+		var filledAt int64
+		if err := rows.Scan(&fill.BuyOrderID, &fill.ListingID, &fill.AppID, &fill.ContextID, &fill.AssetID,
+			&fill.MarketHashName, &fill.Price.Amount, &fill.Price.Currency, &filledAt); err != nil {
+			return nil, fmt.Errorf("读取订购单成交记录失败: %w", err)
+		}
+		fill.FilledAt = time.Unix(filledAt, 0)
+		fills = append(fills, fill)
+	}
+
+	return fills, rows.Err()
+}
+
+// SavePurchase 保存购买流程，ID 为 0 时插入新记录并回填 ID
+func SavePurchase(steamID uint64, purchase *Model.Purchase) error {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return err
+	}
+
+	items, err := json.Marshal(purchase.Items)
+	if err != nil {
+		return fmt.Errorf("序列化购买商品失败: %w", err)
+	}
+	receipt := ""
+	if purchase.Receipt != nil {
+		data, err := json.Marshal(purchase.Receipt)
+		if err != nil {
+			return fmt.Errorf("序列化购买收据失败: %w", err)
+		}
+		receipt = string(data)
+	}
+
+	if purchase.ID == 0 {
+		result, err := db.Exec(`
+			INSERT INTO purchases (steam_id, state, payment_method, items, trans_id, total, currency, payment_url, error, receipt, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, steamID, string(purchase.State), string(purchase.PaymentMethod), string(items), purchase.TransID,
+			purchase.Total.Amount, int(purchase.Total.Currency), purchase.PaymentURL, purchase.Error, receipt,
+			purchase.CreatedAt.Unix(), purchase.UpdatedAt.Unix())
+		if err != nil {
+			return fmt.Errorf("保存购买流程失败: %w", err)
+		}
+		purchase.ID, err = result.LastInsertId()
+		return err
+	}
+
+	_, err = db.Exec(`
+		UPDATE purchases SET state = ?, items = ?, trans_id = ?, total = ?, currency = ?, payment_url = ?, error = ?, receipt = ?, updated_at = ?
+		WHERE id = ? AND steam_id = ?
+	`, string(purchase.State), string(items), purchase.TransID, purchase.Total.Amount, int(purchase.Total.Currency),
+		purchase.PaymentURL, purchase.Error, receipt, purchase.UpdatedAt.Unix(), purchase.ID, steamID)
+	if err != nil {
+		return fmt.Errorf("更新购买流程失败: %w", err)
+	}
+
+	return nil
+}
+
+// GetPurchase 获取购买流程，不存在时返回 nil
+func GetPurchase(steamID uint64, purchaseID int64) (*Model.Purchase, error) {
+	purchases, err := queryPurchases(`WHERE steam_id = ? AND id = ?`, steamID, purchaseID)
+	if err != nil || len(purchases) == 0 {
+		return nil, err
+	}
+	return &purchases[0], nil
+}
+
+// GetUnfinishedPurchases 获取未完成（非 completed、failed 状态）的购买流程，用于程序重启后继续
+func GetUnfinishedPurchases(steamID uint64) ([]Model.Purchase, error) {
+	return queryPurchases(`WHERE steam_id = ? AND state NOT IN (?, ?)`,
+		steamID, string(Model.PurchaseStateCompleted), string(Model.PurchaseStateFailed))
+}
+
+func queryPurchases(where string, args ...any) ([]Model.Purchase, error) {
+	// 确保数据库已初始化
+	if err := ensureDB(); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT id, state, payment_method, items, trans_id, total, currency, payment_url, error, receipt, created_at, updated_at
+		FROM purchases
+	`+where+` ORDER BY id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("查询购买流程失败: %w", err)
+	}
+	defer rows.Close()
+
+	purchases := make([]Model.Purchase, 0)
+	for rows.Next() {
+		var purchase Model.Purchase
+		var state, paymentMethod, items, receipt string
+		var createdAt, updatedAt int64
+		if err := rows.Scan(&purchase.ID, &state, &paymentMethod, &items, &purchase.TransID, &purchase.Total.Amount,
+			&purchase.Total.Currency, &purchase.PaymentURL, &purchase.Error, &receipt, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("读取购买流程失败: %w", err)
+		}
+		purchase.State = Model.PurchaseState(state)
+		purchase.PaymentMethod = Model.PaymentMethod(paymentMethod)
+		if err := json.Unmarshal([]byte(items), &purchase.Items); err != nil {
+			return nil, fmt.Errorf("解析购买商品失败: %w", err)
+		}
+		if receipt != "" {
+			purchase.Receipt = &Model.PurchaseReceipt{}
+			if err := json.Unmarshal([]byte(receipt), purchase.Receipt); err != nil {
+				return nil, fmt.Errorf("解析购买收据失败: %w", err)
+			}
+		}
+		purchase.CreatedAt = time.Unix(createdAt, 0)
+		purchase.UpdatedAt = time.Unix(updatedAt, 0)
+		purchases = append(purchases, purchase)
+	}
+
+	return purchases, rows.Err()
+}
@@ -0,0 +1,213 @@
+package Dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"regexp"
+
+	"github.com/JovanniChen/SteamDB/Steam/Constants"
+	"github.com/JovanniChen/SteamDB/Steam/Errors"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+)
+
+// 市场手续费计算，与 Steam 社区市场前端 market.js 中的算法保持一致，全程使用整数金额
+
+// CalculateAmountToSend 根据卖家希望到手的金额计算买家需支付的金额
+// 对应 market.js 中的 CalculateAmountToSendForDesiredReceivedAmount
+func CalculateAmountToSend(received Model.Money, publisherFee float64, wallet *Model.WalletInfo) Model.MarketFee {
+	if wallet == nil {
+		wallet = &Model.DefaultWalletInfo
+	}
+
+	fees := calculateAmountToSend(received.Amount, publisherFee, wallet)
+	return fees.toMoney(received.Currency)
+}
+
+// CalculateFeeAmount 根据买家支付的金额反推手续费和卖家到手金额
+// 对应 market.js 中的 CalculateFeeAmount
+func CalculateFeeAmount(amount Model.Money, publisherFee float64, wallet *Model.WalletInfo) Model.MarketFee {
+	if wallet == nil {
+		wallet = &Model.DefaultWalletInfo
+	}
+
+	fees := calculateFeeAmount(amount.Amount, publisherFee, wallet)
+	return fees.toMoney(amount.Currency)
+}
+
+// marketFeeAmount 手续费计算的中间结果（1/100单位）
+type marketFeeAmount struct {
+	steamFee     int64
+	publisherFee int64
+	fees         int64
+	amount       int64
+}
+
+// toMoney 转换为指定货币的 Model.MarketFee
+func (f marketFeeAmount) toMoney(currency Model.Currency) Model.MarketFee {
+	return Model.MarketFee{
+		SteamFee:     Model.NewMoney(f.steamFee, currency),
+		PublisherFee: Model.NewMoney(f.publisherFee, currency),
+		Fees:         Model.NewMoney(f.fees, currency),
+		Amount:       Model.NewMoney(f.amount, currency),
+	}
+}
+
+func calculateAmountToSend(receivedAmount int64, publisherFee float64, wallet *Model.WalletInfo) marketFeeAmount {
+	if wallet.WalletFee == 0 {
+		return marketFeeAmount{amount: receivedAmount}
+	}
+
+	steamFee := int64(math.Floor(math.Max(float64(receivedAmount)*wallet.WalletFeePercent, wallet.WalletFeeMinimum) + wallet.WalletFeeBase))
+
+	var publisherFeeAmount int64
+	if publisherFee > 0 {
+		publisherFeeAmount = int64(math.Floor(math.Max(float64(receivedAmount)*publisherFee, 1)))
+	}
+
+	return marketFeeAmount{
+		steamFee:     steamFee,
+		publisherFee: publisherFeeAmount,
+		fees:         steamFee + publisherFeeAmount,
+		amount:       receivedAmount + steamFee + publisherFeeAmount,
+	}
+}
+
+func calculateFeeAmount(amount int64, publisherFee float64, wallet *Model.WalletInfo) marketFeeAmount {
+	if wallet.WalletFee == 0 {
+		return marketFeeAmount{amount: amount}
+	}
+
+	// 先估算卖家到手金额，再逐步逼近买家支付金额
+	estimated := int64((float64(amount) - wallet.WalletFeeBase) / (wallet.WalletFeePercent + publisherFee + 1))
+	everUndershot := false
+	fees := calculateAmountToSend(estimated, publisherFee, wallet)
+
+	for iterations := 0; fees.amount != amount && iterations < 10; iterations++ {
+		if fees.amount > amount {
+			if everUndershot {
+				// 无法精确命中时，差额计入 Steam 手续费
+				fees = calculateAmountToSend(estimated-1, publisherFee, wallet)
+				fees.steamFee += amount - fees.amount
+				fees.fees += amount - fees.amount
+				fees.amount = amount
+				break
+			}
+			estimated--
+		} else {
+			everUndershot = true
+			estimated++
+		}
+		fees = calculateAmountToSend(estimated, publisherFee, wallet)
+	}
+
+	return fees
+}
+
+// GetWalletInfo 获取用于计算手续费的钱包信息
+// 优先使用最近一次从 Steam 获取到的信息，否则返回默认值
+func (d *Dao) GetWalletInfo() Model.WalletInfo {
+	d.feeMu.RLock()
+	defer d.feeMu.RUnlock()
+	if d.walletInfo != nil {
+		return *d.walletInfo
+	}
+	return Model.DefaultWalletInfo
+}
+
+// walletCurrency 当前钱包使用的货币，用于解析没有明确货币符号的价格
+func (d *Dao) walletCurrency() Model.Currency {
+	return Model.Currency(d.GetWalletInfo().WalletCurrency)
+}
+
+// fetchedWalletCurrency 已获取到的钱包货币（g_rgWalletInfo），尚未获取时 ok 为 false
+func (d *Dao) fetchedWalletCurrency() (currency Model.Currency, ok bool) {
+	d.feeMu.RLock()
+	defer d.feeMu.RUnlock()
+	if d.walletInfo == nil || d.walletInfo.WalletCurrency == 0 {
+		return Model.CurrencyUnknown, false
+	}
+	return Model.Currency(d.walletInfo.WalletCurrency), true
+}
+
+// SetWalletInfo 设置用于计算手续费的钱包信息
+func (d *Dao) SetWalletInfo(info Model.WalletInfo) {
+	d.feeMu.Lock()
+	defer d.feeMu.Unlock()
+	d.walletInfo = &info
+}
+
+// SetPublisherFeePercent 为指定游戏设置发行商手续费比例，覆盖钱包信息中的默认值
+func (d *Dao) SetPublisherFeePercent(appID int, percent float64) {
+	d.feeMu.Lock()
+	defer d.feeMu.Unlock()
+	if d.publisherFees == nil {
+		d.publisherFees = make(map[int]float64)
+	}
+	d.publisherFees[appID] = percent
+}
+
+// publisherFeePercent 获取指定游戏的发行商手续费比例
+func (d *Dao) publisherFeePercent(appID int, wallet *Model.WalletInfo) float64 {
+	d.feeMu.RLock()
+	defer d.feeMu.RUnlock()
+	if percent, ok := d.publisherFees[appID]; ok {
+		return percent
+	}
+	return wallet.WalletPublisherFeePercentDefault
+}
+
+// SellerReceiveToBuyerPrice 根据卖家到手金额计算买家支付价格
+func (d *Dao) SellerReceiveToBuyerPrice(appID int, sellerReceive Model.Money) Model.MarketFee {
+	wallet := d.GetWalletInfo()
+	return CalculateAmountToSend(sellerReceive, d.publisherFeePercent(appID, &wallet), &wallet)
+}
+
+// BuyerPriceToSellerReceive 根据买家支付价格计算卖家到手金额和手续费
+func (d *Dao) BuyerPriceToSellerReceive(appID int, buyerPrice Model.Money) Model.MarketFee {
+	wallet := d.GetWalletInfo()
+	return CalculateFeeAmount(buyerPrice, d.publisherFeePercent(appID, &wallet), &wallet)
+}
+
+// FetchWalletInfo 从社区市场首页解析 g_rgWalletInfo 并缓存
+func (d *Dao) FetchWalletInfo() (*Model.WalletInfo, error) {
+	req, err := d.Request(http.MethodGet, Constants.MarketIndex, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.RetryRequest(Constants.Tries, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("获取钱包信息失败: %w", Errors.ErrRateLimited)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, Errors.ResponseError(resp.StatusCode)
+	}
+
+	walletRegex := regexp.MustCompile(`g_rgWalletInfo\s*=\s*(\{.*?\});`)
+	match := walletRegex.FindSubmatch(body)
+	if len(match) < 2 {
+		return nil, fmt.Errorf("未找到钱包信息")
+	}
+
+	var info Model.WalletInfo
+	if err := json.Unmarshal(match[1], &info); err != nil {
+		return nil, fmt.Errorf("解析钱包信息失败: %w", err)
+	}
+
+	d.SetWalletInfo(info)
+	return &info, nil
+}
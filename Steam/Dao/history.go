@@ -0,0 +1,411 @@
+package Dao
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JovanniChen/SteamDB/Steam/Constants"
+	"github.com/JovanniChen/SteamDB/Steam/Errors"
+	"github.com/JovanniChen/SteamDB/Steam/Logger"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+	"github.com/JovanniChen/SteamDB/Steam/Param"
+	"github.com/antchfx/htmlquery"
+	"golang.org/x/net/html"
+)
+
+// marketHistoryPageSize 单次请求交易历史的数量（Steam 接口上限为 100）
+const marketHistoryPageSize = 100
+
+// GetMarketHistory 获取市场交易历史
+// start 为起始位置，count 为需要的条数，超过单页上限时会自动翻页
+func (d *Dao) GetMarketHistory(start int, count int) (*Model.MarketHistoryPage, error) {
+	result := &Model.MarketHistoryPage{
+		Start:  start,
+		Events: make([]Model.MarketHistoryEvent, 0),
+	}
+
+	for offset := start; count <= 0 || len(result.Events) < count; {
+		pageCount := marketHistoryPageSize
+		if count > 0 && count-len(result.Events) < pageCount {
+			pageCount = count - len(result.Events)
+		}
+
+		response, err := d.getMarketHistoryPage(offset, pageCount)
+		if err != nil {
+			return nil, err
+		}
+		result.TotalCount = response.TotalCount
+
+		events, err := parseMarketHistoryHTML(response.ResultsHTML, response.Hovers, d.walletCurrency(), time.Now())
+		if err != nil {
+			return nil, err
+		}
+		result.Events = append(result.Events, events...)
+
+		offset += len(events)
+		if len(events) == 0 || offset >= response.TotalCount {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// marketHistorySyncState 市场交易历史同步游标在 sync_state 中的名称
+const marketHistorySyncState = "market_history"
+
+// SyncMarketHistory 增量同步市场交易历史到数据库
+// 从最新的记录开始翻页，直到遇到上一次完整同步时最新的记录（游标）或到达末尾，返回本次新增的事件
+// 同步完成后才更新游标，中途失败时下一次同步会补齐中间缺失的记录
+func (d *Dao) SyncMarketHistory() ([]Model.MarketHistoryEvent, error) {
+	steamID := d.GetSteamID()
+	newEvents := make([]Model.MarketHistoryEvent, 0)
+
+	cursor, err := GetSyncState(steamID, marketHistorySyncState)
+	if err != nil {
+		return newEvents, err
+	}
+
+	newest := ""
+	for start := 0; ; {
+		response, err := d.getMarketHistoryPage(start, marketHistoryPageSize)
+		if err != nil {
+			return newEvents, err
+		}
+
+		events, err := parseMarketHistoryHTML(response.ResultsHTML, response.Hovers, d.walletCurrency(), time.Now())
+		if err != nil {
+			return newEvents, err
+		}
+
+		reachedCursor := false
+		for _, event := range events {
+			if newest == "" {
+				newest = event.RowID
+			}
+			if cursor != "" && event.RowID == cursor {
+				reachedCursor = true
+				break
+			}
+
+			inserted, err := SaveMarketHistoryEvent(steamID, &event)
+			if err != nil {
+				return newEvents, err
+			}
+			if inserted {
+				newEvents = append(newEvents, event)
+			}
+		}
+
+		start += len(events)
+		if reachedCursor || len(events) == 0 || start >= response.TotalCount {
+			break
+		}
+	}
+
+	if newest != "" {
+		if err := SaveSyncState(steamID, marketHistorySyncState, newest); err != nil {
+			return newEvents, err
+		}
+	}
+
+	Logger.Infof("同步用户[%s]的市场交易历史完成，新增: %d", d.GetUsername(), len(newEvents))
+	return newEvents, nil
+}
+
+// getMarketHistoryPage 获取指定起始位置的一页交易历史
+func (d *Dao) getMarketHistoryPage(start int, count int) (*Model.MarketHistoryResponse, error) {
+	params := Param.Params{}
+	params.SetString("query", "")
+	params.SetInt64("start", int64(start))
+	params.SetInt64("count", int64(count))
+
+	req, err := d.Request(http.MethodGet, Constants.GetMarketHistory+"?"+params.ToUrl(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.RetryRequest(Constants.Tries, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("获取交易历史失败: %w", Errors.ErrRateLimited)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, Errors.ResponseError(resp.StatusCode)
+	}
+
+	var response Model.MarketHistoryResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		Logger.Error("JSON解析错误:", err)
+		return nil, err
+	}
+
+	if !response.Success {
+		return nil, fmt.Errorf("获取交易历史失败，start=%d", start)
+	}
+
+	return &response, nil
+}
+
+// parseMarketHistoryHTML 解析交易历史HTML
+// hovers 中的 CreateItemHoverFromContainer 调用记录了每一行对应的 appid/contextid/assetid
+func parseMarketHistoryHTML(resultsHTML string, hovers string, currency Model.Currency, now time.Time) ([]Model.MarketHistoryEvent, error) {
+	events := make([]Model.MarketHistoryEvent, 0)
+
+	doc, err := htmlquery.Parse(strings.NewReader(resultsHTML))
+	if err != nil {
+		return nil, fmt.Errorf("解析交易历史HTML失败: %w", err)
+	}
+
+	assets := parseMarketHistoryHovers(hovers)
+
+	rows := htmlquery.Find(doc, "//div[contains(@class, 'market_recent_listing_row') and starts-with(@id, 'history_row_')]")
+	for _, row := range rows {
+		rowID := htmlquery.SelectAttr(row, "id")
+		event := Model.MarketHistoryEvent{
+			RowID: rowID,
+			Type:  Model.MarketHistoryUnknown,
+		}
+
+		// history_row_{listingid}_{eventid}
+		if parts := strings.Split(strings.TrimPrefix(rowID, "history_row_"), "_"); len(parts) > 0 {
+			event.ListingID = parts[0]
+		}
+
+		if asset, ok := assets[rowID]; ok {
+			event.AppID = asset.AppID
+			event.ContextID = asset.ContextID
+			event.AssetID = asset.AssetID
+		}
+
+		if node := htmlquery.FindOne(row, ".//span[contains(@class, 'market_listing_item_name')]"); node != nil {
+			event.ItemName = strings.TrimSpace(htmlquery.InnerText(node))
+		}
+		if node := htmlquery.FindOne(row, ".//span[contains(@class, 'market_listing_game_name')]"); node != nil {
+			event.GameName = strings.TrimSpace(htmlquery.InnerText(node))
+		}
+		if node := htmlquery.FindOne(row, ".//span[contains(@class, 'market_listing_price')]"); node != nil {
+			event.PriceText = strings.TrimSpace(htmlquery.InnerText(node))
+			event.Price = parsePrice(event.PriceText, currency)
+		}
+
+		gainOrLoss := ""
+		if node := htmlquery.FindOne(row, ".//div[contains(@class, 'market_listing_gainorloss')]"); node != nil {
+			gainOrLoss = strings.TrimSpace(htmlquery.InnerText(node))
+		}
+
+		whoActedWith := ""
+		if node := htmlquery.FindOne(row, ".//div[contains(@class, 'market_listing_whoactedwith')]"); node != nil {
+			whoActedWith = strings.TrimSpace(htmlquery.InnerText(node))
+			if link := htmlquery.FindOne(node, ".//a[@href]"); link != nil {
+				event.CounterpartyURL = htmlquery.SelectAttr(link, "href")
+			}
+			if nameBlock := htmlquery.FindOne(node, ".//div[contains(@class, 'market_listing_whoactedwith_name_block')]"); nameBlock != nil {
+				event.CounterpartyName = parseCounterpartyName(nameBlock)
+			}
+		}
+
+		event.Type = parseMarketHistoryEventType(gainOrLoss, whoActedWith)
+
+		// 第一个日期为事件发生日期，第二个为上架日期
+		dateNodes := htmlquery.Find(row, ".//div[contains(@class, 'market_listing_listed_date')]")
+		if len(dateNodes) > 0 {
+			event.ActedOn = parseMarketHistoryDate(htmlquery.InnerText(dateNodes[0]), now)
+		}
+		if len(dateNodes) > 1 {
+			event.ListedOn = parseMarketHistoryDate(htmlquery.InnerText(dateNodes[1]), now)
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// marketHistoryAsset 交易历史中一行对应的物品
+type marketHistoryAsset struct {
+	AppID     int
+	ContextID string
+	AssetID   string
+}
+
+// parseMarketHistoryHovers 解析 hovers 脚本，返回 行ID -> 物品 的映射
+func parseMarketHistoryHovers(hovers string) map[string]marketHistoryAsset {
+	assets := make(map[string]marketHistoryAsset)
+
+	hoverRegex := regexp.MustCompile(`CreateItemHoverFromContainer\(\s*g_rgAssets,\s*'(history_row_\d+_\d+)_name',\s*(\d+),\s*'(\d+)',\s*'(\d+)'`)
+	for _, match := range hoverRegex.FindAllStringSubmatch(hovers, -1) {
+		appID, _ := strconv.Atoi(match[2])
+		assets[match[1]] = marketHistoryAsset{
+			AppID:     appID,
+			ContextID: match[3],
+			AssetID:   match[4],
+		}
+	}
+
+	return assets
+}
+
+// parseMarketHistoryEventType 根据盈亏标记和交易对象描述判断事件类型
+func parseMarketHistoryEventType(gainOrLoss string, whoActedWith string) Model.MarketHistoryEventType {
+	switch gainOrLoss {
+	case "+":
+		return Model.MarketHistoryPurchased
+	case "-":
+		return Model.MarketHistorySold
+	}
+
+	lower := strings.ToLower(whoActedWith)
+	switch {
+	case strings.Contains(lower, "listing created") || strings.Contains(whoActedWith, "已创建"):
+		return Model.MarketHistoryListed
+	case strings.Contains(lower, "listing canceled") || strings.Contains(lower, "listing cancelled") || strings.Contains(whoActedWith, "已取消"):
+		return Model.MarketHistoryCancelled
+	case strings.Contains(lower, "buyer") || strings.Contains(whoActedWith, "买家"):
+		return Model.MarketHistorySold
+	case strings.Contains(lower, "seller") || strings.Contains(whoActedWith, "卖家"):
+		return Model.MarketHistoryPurchased
+	}
+
+	return Model.MarketHistoryUnknown
+}
+
+// parseCounterpartyName 从 "Buyer:<br/>name" 结构中取出交易对方昵称
+func parseCounterpartyName(nameBlock *html.Node) string {
+	afterBreak := false
+	name := ""
+	for child := nameBlock.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "br" {
+			afterBreak = true
+			continue
+		}
+		if afterBreak {
+			name += htmlquery.InnerText(child)
+		}
+	}
+	return strings.TrimSpace(name)
+}
+
+var (
+	historyDateEnglishRegex = regexp.MustCompile(`(\d{1,2})\s+([A-Za-z]{3})[A-Za-z]*,?\s*(\d{4})?`)
+	historyDateUSRegex      = regexp.MustCompile(`([A-Za-z]{3})[A-Za-z]*\s+(\d{1,2}),?\s*(\d{4})?`)
+	historyDateChineseRegex = regexp.MustCompile(`(?:(\d{4})\s*年\s*)?(\d{1,2})\s*月\s*(\d{1,2})\s*日`)
+)
+
+// parseMarketHistoryDate 解析交易历史中的日期（如 "17 Oct"、"Oct 17"、"10月17日"）
+// 页面通常不显示年份，此时按 now 推断：晚于当前日期的月日视为去年
+func parseMarketHistoryDate(text string, now time.Time) time.Time {
+	text = strings.TrimSpace(text)
+
+	var year, day int
+	var month time.Month
+
+	if match := historyDateChineseRegex.FindStringSubmatch(text); match != nil {
+		year, _ = strconv.Atoi(match[1])
+		m, _ := strconv.Atoi(match[2])
+		month = time.Month(m)
+		day, _ = strconv.Atoi(match[3])
+	} else if match := historyDateEnglishRegex.FindStringSubmatch(text); match != nil {
+		day, _ = strconv.Atoi(match[1])
+		month = parseShortMonth(match[2])
+		year, _ = strconv.Atoi(match[3])
+	} else if match := historyDateUSRegex.FindStringSubmatch(text); match != nil {
+		month = parseShortMonth(match[1])
+		day, _ = strconv.Atoi(match[2])
+		year, _ = strconv.Atoi(match[3])
+	}
+
+	if month < time.January || month > time.December || day == 0 {
+		return time.Time{}
+	}
+
+	if year == 0 {
+		year = now.Year()
+		if time.Date(year, month, day, 0, 0, 0, 0, now.Location()).After(now) {
+			year--
+		}
+	}
+
+	return time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+}
+
+// parseShortMonth 将英文月份缩写转换为 time.Month，无法识别时返回0
+func parseShortMonth(s string) time.Month {
+	t, err := time.Parse("Jan", strings.ToUpper(s[:1])+strings.ToLower(s[1:3]))
+	if err != nil {
+		return 0
+	}
+	return t.Month()
+}
+
+// marketHistoryCSVHeader 导出CSV的表头
+var marketHistoryCSVHeader = []string{
+	"row_id", "listing_id", "type", "appid", "contextid", "assetid", "item_name", "game_name",
+	"price", "currency", "price_text", "counterparty_name", "counterparty_url", "acted_on", "listed_on",
+}
+
+// ExportMarketHistoryCSV 将交易历史导出为CSV
+func ExportMarketHistoryCSV(w io.Writer, events []Model.MarketHistoryEvent) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(marketHistoryCSVHeader); err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		record := []string{
+			event.RowID,
+			event.ListingID,
+			string(event.Type),
+			strconv.Itoa(event.AppID),
+			event.ContextID,
+			event.AssetID,
+			event.ItemName,
+			event.GameName,
+			event.Price.Number(),
+			event.Price.Currency.Code(),
+			event.PriceText,
+			event.CounterpartyName,
+			event.CounterpartyURL,
+			formatHistoryDate(event.ActedOn),
+			formatHistoryDate(event.ListedOn),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportMarketHistoryJSON 将交易历史导出为JSON
+func ExportMarketHistoryJSON(w io.Writer, events []Model.MarketHistoryEvent) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(events)
+}
+
+// formatHistoryDate 格式化日期，零值输出空字符串
+func formatHistoryDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
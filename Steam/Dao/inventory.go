@@ -0,0 +1,349 @@
+package Dao
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/JovanniChen/SteamDB/Steam/Constants"
+	"github.com/JovanniChen/SteamDB/Steam/Errors"
+	"github.com/JovanniChen/SteamDB/Steam/Logger"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+	"github.com/JovanniChen/SteamDB/Steam/Param"
+)
+
+// inventoryPageSize 单次请求库存的数量（Steam 接口上限为 2000）
+const inventoryPageSize = 2000
+
+// InventoryIterator 库存迭代器
+// 按 more_items / last_assetid 自动翻页，逐个返回带完整描述的资产，不做任何过滤
+//
+// 用法：
+//
+//	it := d.NewInventoryIterator(appID, contextID)
+//	for it.Next() {
+//		item := it.Item()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type InventoryIterator struct {
+	dao       *Dao
+	steamID   uint64
+	appID     int
+	contextID int
+
+	startAssetID string // 下一页的起始资产ID
+	finished     bool   // 是否已取完所有页
+	totalCount   int    // 库存物品总数
+
+	page  []Model.InventoryItem
+	index int
+	item  Model.InventoryItem
+	err   error
+}
+
+// NewInventoryIterator 创建当前登录账号指定游戏/上下文的库存迭代器
+func (d *Dao) NewInventoryIterator(appID int, contextID int) *InventoryIterator {
+	return d.NewUserInventoryIterator(d.GetSteamID(), appID, contextID)
+}
+
+// NewUserInventoryIterator 创建任意账号指定游戏/上下文的库存迭代器，对方库存需要公开
+func (d *Dao) NewUserInventoryIterator(steamID uint64, appID int, contextID int) *InventoryIterator {
+	return &InventoryIterator{
+		dao:       d,
+		steamID:   steamID,
+		appID:     appID,
+		contextID: contextID,
+	}
+}
+
+// Next 移动到下一个物品，没有更多物品或出错时返回 false
+func (it *InventoryIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.index >= len(it.page) {
+		if it.finished {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	it.item = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Item 当前物品
+func (it *InventoryIterator) Item() Model.InventoryItem {
+	return it.item
+}
+
+// Err 迭代过程中遇到的错误
+func (it *InventoryIterator) Err() error {
+	return it.err
+}
+
+// TotalCount 库存物品总数（取到第一页后有效）
+func (it *InventoryIterator) TotalCount() int {
+	return it.totalCount
+}
+
+// fetch 获取下一页库存
+func (it *InventoryIterator) fetch() error {
+	response, err := it.dao.getInventoryPage(it.steamID, it.appID, it.contextID, it.startAssetID, inventoryPageSize)
+	if err != nil {
+		return err
+	}
+
+	it.totalCount = response.TotalInventoryCount
+	it.page = mergeInventoryPage(response)
+	it.index = 0
+
+	if response.MoreItems == 1 && response.LastAssetID != "" {
+		it.startAssetID = response.LastAssetID
+	} else {
+		it.finished = true
+	}
+
+	return nil
+}
+
+// GetInventoryItems 获取当前登录账号指定游戏/上下文的全部库存
+// filters 为调用方提供的过滤条件，全部满足时才保留，不传则返回所有物品
+func (d *Dao) GetInventoryItems(appID int, contextID int, filters ...Model.InventoryFilter) ([]Model.InventoryItem, error) {
+	return collectInventory(d.NewInventoryIterator(appID, contextID), filters...)
+}
+
+// GetUserInventoryItems 获取任意账号指定游戏/上下文的全部公开库存
+func (d *Dao) GetUserInventoryItems(steamID uint64, appID int, contextID int, filters ...Model.InventoryFilter) ([]Model.InventoryItem, error) {
+	return collectInventory(d.NewUserInventoryIterator(steamID, appID, contextID), filters...)
+}
+
+// collectInventory 遍历迭代器并按过滤条件收集物品
+func collectInventory(it *InventoryIterator, filters ...Model.InventoryFilter) ([]Model.InventoryItem, error) {
+	items := make([]Model.InventoryItem, 0)
+	for it.Next() {
+		item := it.Item()
+		if Model.MatchInventoryFilters(item, filters...) {
+			items = append(items, item)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// getInventoryPage 获取一页库存
+func (d *Dao) getInventoryPage(steamID uint64, appID int, contextID int, startAssetID string, count int) (*Model.InventoryResponse, error) {
+	params := Param.Params{}
+	params.SetString("l", "english")
+	params.SetInt64("count", int64(count))
+	if startAssetID != "" {
+		params.SetString("start_assetid", startAssetID)
+	}
+
+	inventoryUrl := fmt.Sprintf("%s/%d/%d/%d?%s", Constants.GetInventory, steamID, appID, contextID, params.ToUrl())
+	req, err := d.Request(http.MethodGet, inventoryUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建库存请求失败: %w", err)
+	}
+
+	resp, err := d.RetryRequest(Constants.Tries, req)
+	if err != nil {
+		return nil, fmt.Errorf("执行库存请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取库存响应失败: %w", err)
+	}
+
+	// 检查是否为GZIP压缩数据
+	if len(body) > 2 && body[0] == 0x1f && body[1] == 0x8b {
+		reader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("解压库存响应失败: %w", err)
+		}
+		defer reader.Close()
+		if body, err = io.ReadAll(reader); err != nil {
+			return nil, fmt.Errorf("解压库存响应失败: %w", err)
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests:
+		Logger.Warnf("获取库存 [%d/%d/%d] 遇到速率限制 (429)", steamID, appID, contextID)
+		return nil, fmt.Errorf("获取库存失败: %w", Errors.ErrRateLimited)
+	case http.StatusForbidden:
+		// 查看他人库存时 403 表示对方库存未公开
+		if steamID != d.GetSteamID() {
+			return nil, fmt.Errorf("获取库存 [%d] 失败: %w", steamID, Errors.ErrInventoryPrivate)
+		}
+		return nil, fmt.Errorf("获取库存失败: %w", Errors.ErrAuthorizationFailed)
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("获取库存失败: %w", Errors.ErrAuthorizationFailed)
+	default:
+		return nil, fmt.Errorf("获取库存失败,返回状态码: %d", resp.StatusCode)
+	}
+
+	var response Model.InventoryResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("解析库存响应失败: %w", err)
+	}
+
+	if response.Success != 1 {
+		return nil, fmt.Errorf("库存API返回失败，success=%d，error=%s", response.Success, response.Error)
+	}
+
+	return &response, nil
+}
+
+// mergeInventoryPage 将一页中的资产和描述合并为完整物品
+func mergeInventoryPage(response *Model.InventoryResponse) []Model.InventoryItem {
+	descMap := make(map[string]Model.Description, len(response.Descriptions))
+	for _, desc := range response.Descriptions {
+		descMap[desc.ClassID+"_"+desc.InstanceID] = desc
+	}
+
+	items := make([]Model.InventoryItem, 0, len(response.Assets))
+	for _, asset := range response.Assets {
+		amount, err := strconv.ParseInt(asset.Amount, 10, 64)
+		if err != nil {
+			amount = 1
+		}
+
+		item := Model.InventoryItem{
+			AppID:      asset.AppID,
+			ContextID:  asset.ContextID,
+			AssetID:    asset.AssetID,
+			ClassID:    asset.ClassID,
+			InstanceID: asset.InstanceID,
+			Amount:     amount,
+		}
+		if desc, ok := descMap[asset.ClassID+"_"+asset.InstanceID]; ok {
+			item.Description = desc
+		} else {
+			Logger.Debugf("资产 %s 缺少描述信息", asset.AssetID)
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// inventoryAppContextData g_rgAppContextData 中单个游戏的原始结构
+type inventoryAppContextData struct {
+	AppID            int    `json:"appid"`
+	Name             string `json:"name"`
+	Icon             string `json:"icon"`
+	Link             string `json:"link"`
+	AssetCount       int    `json:"asset_count"`
+	TradePermissions string `json:"trade_permissions"`
+	LoadFailed       int    `json:"load_failed"`
+	OwnerOnly        bool   `json:"owner_only"`
+	RgContexts       map[string]struct {
+		AssetCount int    `json:"asset_count"`
+		ID         string `json:"id"`
+		Name       string `json:"name"`
+	} `json:"rgContexts"`
+}
+
+// GetInventoryContexts 获取账号拥有库存的游戏及其上下文
+// 通过解析个人资料库存页面中的 g_rgAppContextData 得到，steamID 为 0 时查询当前登录账号
+func (d *Dao) GetInventoryContexts(steamID uint64) ([]Model.InventoryApp, error) {
+	if steamID == 0 {
+		steamID = d.GetSteamID()
+	}
+
+	inventoryUrl := fmt.Sprintf("%s/%d/inventory/", Constants.Profiles, steamID)
+	req, err := d.Request(http.MethodGet, inventoryUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建库存页面请求失败: %w", err)
+	}
+
+	resp, err := d.RetryRequest(Constants.Tries, req)
+	if err != nil {
+		return nil, fmt.Errorf("执行库存页面请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取库存页面失败: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("获取库存页面失败: %w", Errors.ErrRateLimited)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, Errors.ResponseError(resp.StatusCode)
+	}
+
+	return parseInventoryAppContextData(string(body))
+}
+
+// parseInventoryAppContextData 从库存页面HTML中解析 g_rgAppContextData
+func parseInventoryAppContextData(htmlContent string) ([]Model.InventoryApp, error) {
+	contextRegex := regexp.MustCompile(`g_rgAppContextData\s*=\s*(\{.*?\}|\[\]);\s*\n`)
+	match := contextRegex.FindStringSubmatch(htmlContent)
+	if len(match) < 2 {
+		// 库存未公开时页面不包含 g_rgAppContextData
+		if strings.Contains(htmlContent, "profile_private_info") {
+			return nil, Errors.ErrInventoryPrivate
+		}
+		return nil, Errors.ErrInventoryAppContext
+	}
+
+	// 没有任何库存时为空数组
+	if match[1] == "[]" {
+		return []Model.InventoryApp{}, nil
+	}
+
+	var raw map[string]inventoryAppContextData
+	if err := json.Unmarshal([]byte(match[1]), &raw); err != nil {
+		return nil, fmt.Errorf("解析 g_rgAppContextData 失败: %w", err)
+	}
+
+	apps := make([]Model.InventoryApp, 0, len(raw))
+	for _, data := range raw {
+		app := Model.InventoryApp{
+			AppID:            data.AppID,
+			Name:             data.Name,
+			Icon:             data.Icon,
+			Link:             data.Link,
+			AssetCount:       data.AssetCount,
+			TradePermissions: data.TradePermissions,
+			LoadFailed:       data.LoadFailed,
+			OwnerOnly:        data.OwnerOnly,
+			Contexts:         make([]Model.InventoryContext, 0, len(data.RgContexts)),
+		}
+		for _, ctx := range data.RgContexts {
+			app.Contexts = append(app.Contexts, Model.InventoryContext{
+				ID:         ctx.ID,
+				Name:       ctx.Name,
+				AssetCount: ctx.AssetCount,
+			})
+		}
+		sort.Slice(app.Contexts, func(i, j int) bool { return app.Contexts[i].ID < app.Contexts[j].ID })
+		apps = append(apps, app)
+	}
+
+	sort.Slice(apps, func(i, j int) bool { return apps[i].AppID < apps[j].AppID })
+	return apps, nil
+}
@@ -141,28 +141,28 @@ func (d *Dao) GetLanguage() string {
 
 // GetBalance 获取用户余额
 // 返回值：余额
-func (d *Dao) GetBalance() int {
+func (d *Dao) GetBalance() Model.Money {
 	userInfo, err := d.getUserInfo()
 	if err != nil {
-		return 0
+		return Model.Money{}
 	}
 	return userInfo.Balance
 }
 
 // GetWaitBalance 获取待处理余额
 // 返回值：待处理余额
-func (d *Dao) GetWaitBalance() int {
+func (d *Dao) GetWaitBalance() Model.Money {
 	userInfo, err := d.getUserInfo()
 	if err != nil {
-		return 0
+		return Model.Money{}
 	}
 	return userInfo.WaitBalance
 }
 
-func (d *Dao) GetBalanceAndWaitBalance() (int, int) {
+func (d *Dao) GetBalanceAndWaitBalance() (Model.Money, Model.Money) {
 	userInfo, err := d.getUserInfo()
 	if err != nil {
-		return 0, 0
+		return Model.Money{}, Model.Money{}
 	}
 	return userInfo.Balance, userInfo.WaitBalance
 }
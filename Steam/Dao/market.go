@@ -1,10 +1,10 @@
 package Dao
 
 import (
-	"bytes"
 	"compress/flate"
 	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -22,13 +22,127 @@ import (
 	"github.com/JovanniChen/SteamDB/Steam/Utils"
 )
 
+// myListingsPageSize 单次请求上架列表的数量（Steam 接口上限为 100）
+const myListingsPageSize = 100
+
 // GetMyListings 获取用户的上架列表
-// 返回两个列表：已上架的物品和等待确认的物品
-func (d *Dao) GetMyListings() (activeListings []Model.MyListingReponse, err error) {
+// 按页遍历所有已上架物品，并一并解析等待确认的物品和进行中的订购单
+// 该方法只读取数据，不会对任何上架物品做删除等操作
+func (d *Dao) GetMyListings() (*Model.MyListings, error) {
 	Logger.Infof("获取用户 %s 的上架列表", d.GetUsername())
+
+	result := &Model.MyListings{
+		Active:    make([]Model.MyListingReponse, 0),
+		Pending:   make([]Model.MyListingReponse, 0),
+		BuyOrders: make([]Model.BuyOrder, 0),
+	}
+
+	// 不同页返回的等待确认物品和订购单可能重复，按ID去重
+	seenActive := make(map[string]struct{})
+	seenPending := make(map[string]struct{})
+	seenBuyOrders := make(map[string]struct{})
+	currency := d.walletCurrency()
+
+	for start := 0; ; {
+		response, err := d.getMyListingsPage(start, myListingsPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		activeListings, pendingListings, err := parseSteamMarketHTML(response.ResultsHTML, currency)
+		if err != nil {
+			Logger.Error("从html中解析上架物品失败:", err)
+			return nil, err
+		}
+
+		assets := parseListingAssets(response.Assets)
+		fillListingAssets(activeListings, assets)
+		fillListingAssets(pendingListings, assets)
+
+		for _, listing := range activeListings {
+			if _, ok := seenActive[listing.ListingID]; ok {
+				continue
+			}
+			seenActive[listing.ListingID] = struct{}{}
+			result.Active = append(result.Active, listing)
+		}
+		for _, listing := range pendingListings {
+			if _, ok := seenPending[listing.ListingID]; ok {
+				continue
+			}
+			seenPending[listing.ListingID] = struct{}{}
+			result.Pending = append(result.Pending, listing)
+		}
+		for _, order := range parseBuyOrdersFromHTML(response.ResultsHTML, currency) {
+			if _, ok := seenBuyOrders[order.BuyOrderID]; ok {
+				continue
+			}
+			seenBuyOrders[order.BuyOrderID] = struct{}{}
+			result.BuyOrders = append(result.BuyOrders, order)
+		}
+
+		// 本页没有新数据或已取完全部已上架物品时结束
+		pageSize := response.PageSize
+		if pageSize <= 0 {
+			pageSize = myListingsPageSize
+		}
+		start += pageSize
+		if len(activeListings) == 0 || start >= response.TotalCount {
+			break
+		}
+	}
+
+	Logger.Infof("获取用户[%s]的上架列表完成，已上架: %d，待确认: %d，订购单: %d",
+		d.GetUsername(), len(result.Active), len(result.Pending), len(result.BuyOrders))
+
+	return result, nil
+}
+
+// parseListingAssets 解析上架列表返回的资产信息，按 assetid 索引
+// 没有上架物品时 Steam 返回空数组，解析失败时返回空表，不影响上架列表本身
+func parseListingAssets(raw json.RawMessage) map[string]Model.ListingAsset {
+	result := make(map[string]Model.ListingAsset)
+	if len(raw) == 0 {
+		return result
+	}
+
+	var apps map[string]map[string]map[string]Model.ListingAsset
+	if err := json.Unmarshal(raw, &apps); err != nil {
+		return result
+	}
+	for _, contexts := range apps {
+		for _, assets := range contexts {
+			for id, asset := range assets {
+				if asset.AssetID == "" {
+					asset.AssetID = id
+				}
+				result[asset.AssetID] = asset
+			}
+		}
+	}
+	return result
+}
+
+// fillListingAssets 用资产信息补全上架物品的上下文ID、类别ID、实例ID以及上架前的资产ID
+func fillListingAssets(listings []Model.MyListingReponse, assets map[string]Model.ListingAsset) {
+	for i := range listings {
+		asset, ok := assets[listings[i].AssetID]
+		if !ok {
+			continue
+		}
+		listings[i].ContextID = asset.ContextID
+		listings[i].ClassID = asset.ClassID
+		listings[i].InstanceID = asset.InstanceID
+		listings[i].OriginalAssetID = asset.UnownedID
+		listings[i].OriginalContextID = asset.UnownedContextID
+	}
+}
+
+// getMyListingsPage 获取指定起始位置的一页上架列表
+func (d *Dao) getMyListingsPage(start int, count int) (*Model.GetMyListingResponse, error) {
 	params := Param.Params{}
-	params.SetString("count", "50")
-	// params.SetString("norender", "1")
+	params.SetInt64("start", int64(start))
+	params.SetInt64("count", int64(count))
 
 	req, err := d.NewRequest(http.MethodGet, Constants.GetMyListings+"?"+params.ToUrl(), nil)
 	if err != nil {
@@ -52,49 +166,50 @@ func (d *Dao) GetMyListings() (activeListings []Model.MyListingReponse, err erro
 		return nil, err
 	}
 
-	fmt.Println(string(body))
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("获取上架列表失败: %w", Errors.ErrRateLimited)
+	}
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, Errors.ResponseError(resp.StatusCode)
 	}
 
 	var response Model.GetMyListingResponse
-	err = json.Unmarshal(body, &response)
-	if err != nil {
+	if err := json.Unmarshal(body, &response); err != nil {
 		Logger.Error("JSON解析错误:", err)
 		return nil, err
 	}
 
-	// Logger.Infof("[特殊打印	]获取用户[%s]的上架列表的html，数量: %s", d.GetUsername(), response.ResultsHTML)
+	if !response.Success {
+		return nil, fmt.Errorf("获取上架列表失败，start=%d", start)
+	}
 
-	activeListings, pendingListings, err := parseSteamMarketHTML(response.ResultsHTML)
+	return &response, nil
+}
+
+// RemovePendingListings 删除所有等待确认的上架物品
+// 需要调用方显式调用，返回成功删除的 Listing ID 列表
+func (d *Dao) RemovePendingListings() ([]string, error) {
+	listings, err := d.GetMyListings()
 	if err != nil {
-		Logger.Error("从html中解析上架物品失败:", err)
 		return nil, err
 	}
 
-	Logger.Infof("[特殊打印]获取用户[%s]的已上架列表，数量: %d", d.GetUsername(), len(activeListings))
-	for _, listing := range activeListings {
-		Logger.Infof("[特殊打印]已上架列表: %+v", listing)
-	}
-	Logger.Infof("[特殊打印]获取用户[%s]的待确认上架列表，数量: %d", d.GetUsername(), len(pendingListings))
-	for _, listing := range pendingListings {
-		Logger.Infof("[特殊打印]待确认上架列表: %+v", listing)
-	}
-
-	for _, listing := range pendingListings {
+	removed := make([]string, 0, len(listings.Pending))
+	for _, listing := range listings.Pending {
 		Logger.Infof("删除用户 [%s] 的等待确认物品，creatorId: %s", d.GetUsername(), listing.ListingID)
-		err := d.RemoveMyListings(listing.ListingID)
-		if err != nil {
+		if err := d.RemoveMyListings(listing.ListingID); err != nil {
 			Logger.Errorf("删除listing失败 [%s]: %v", listing.ListingID, err)
-			// 可以选择继续删除下一个，或者返回错误
 			continue
 		}
-		Logger.Infof("成功删除listing [%s]", listing.ListingID)
-		fmt.Printf("%+v\n", listing)
+		removed = append(removed, listing.ListingID)
+	}
+
+	if len(removed) != len(listings.Pending) {
+		return removed, fmt.Errorf("删除等待确认物品未全部成功: 待删除[%d] != 已删除[%d]", len(listings.Pending), len(removed))
 	}
 
-	return activeListings, nil
+	return removed, nil
 }
 
 // Remove 删除上架物品
@@ -133,6 +248,10 @@ func (d *Dao) RemoveMyListings(creatorId string) error {
 
 	// Logger.Debug(string(body))
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("下架失败: %w", Errors.ErrRateLimited)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return Errors.ResponseError(resp.StatusCode)
 	}
@@ -140,10 +259,175 @@ func (d *Dao) RemoveMyListings(creatorId string) error {
 	return nil
 }
 
-func (d *Dao) RemoveAllMyListings() error {
+// RemoveAllMyListings 批量下架已上架和等待确认的物品
+// opts 为 nil 时下架全部物品；可按游戏ID和市场名称过滤
+// 每次下架之间按 Interval 限速，遇到 429 时退避后重试
+// 返回值：成功下架的数量和可能的错误
+func (d *Dao) RemoveAllMyListings(opts *Model.RemoveListingsOptions) (int, error) {
+	if opts == nil {
+		opts = &Model.RemoveListingsOptions{}
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = Constants.ProcessInterval
+	}
+
+	listings, err := d.GetMyListings()
+	if err != nil {
+		return 0, err
+	}
+
+	targets := make([]Model.MyListingReponse, 0, len(listings.Active)+len(listings.Pending))
+	for _, listing := range append(listings.Active, listings.Pending...) {
+		if opts.AppID != 0 && listing.AppID != opts.AppID {
+			continue
+		}
+		if opts.MarketHashName != "" && listing.MarketHashName != opts.MarketHashName {
+			continue
+		}
+		targets = append(targets, listing)
+	}
+
+	Logger.Infof("用户 [%s] 开始批量下架，共 %d 个物品", d.GetUsername(), len(targets))
+
+	var removed int
+	var failed int
+	for i, listing := range targets {
+		if i > 0 {
+			time.Sleep(interval)
+		}
+
+		err := d.RemoveMyListings(listing.ListingID)
+		// 速率受限时逐步加大等待时间后重试
+		for try := 1; err != nil && Errors.IsRateLimitError(err) && try < Constants.Tries; try++ {
+			Logger.Warnf("用户 [%s] 下架物品遇到速率限制，第 %d 次重试", d.GetUsername(), try)
+			time.Sleep(interval * time.Duration(10*try))
+			err = d.RemoveMyListings(listing.ListingID)
+		}
+
+		if err != nil {
+			failed++
+			Logger.Errorf("下架失败 [%s]: %v", listing.ListingID, err)
+		} else {
+			removed++
+		}
+
+		if opts.OnProgress != nil {
+			opts.OnProgress(i+1, len(targets), listing, err)
+		}
+	}
+
+	if failed > 0 {
+		return removed, fmt.Errorf("批量下架未全部成功: 待下架[%d] != 已下架[%d]", len(targets), removed)
+	}
+
+	return removed, nil
+}
+
+// CancelBuyOrder 取消订购单
+// 参数：orderID - 订购单ID（可通过 GetMyListings 获取）
+func (d *Dao) CancelBuyOrder(orderID string) error {
+	Logger.Infof("用户 [%s] 取消订购单，buyOrderId: %s", d.GetUsername(), orderID)
+
+	if d.GetLoginCookies()["steamcommunity.com"] == nil {
+		return errors.New("steamcommunity.com cookie not found")
+	}
+
+	params := Param.Params{}
+	params.SetString("sessionid", d.GetLoginCookies()["steamcommunity.com"].SessionId)
+	params.SetString("buy_orderid", orderID)
+
+	req, err := d.Request(http.MethodPost, Constants.CancelBuyOrder, strings.NewReader(params.Encode()))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Add("origin", Constants.CommunityOrigin)
+	req.Header.Set("referer", fmt.Sprintf("%s/market", Constants.CommunityOrigin))
+
+	resp, err := d.RetryRequest(Constants.Tries, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("取消订购单失败: %w", Errors.ErrRateLimited)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Errors.ResponseError(resp.StatusCode)
+	}
+
+	var response Model.CancelBuyOrderResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("解析取消订购单响应失败: %w", err)
+	}
+
+	if response.Success != 1 {
+		return fmt.Errorf("取消订购单失败，success=%d", response.Success)
+	}
+
 	return nil
 }
 
+// GetBuyOrderStatus 查询订购单的成交进度
+func (d *Dao) GetBuyOrderStatus(buyOrderID string) (*Model.BuyOrderStatus, error) {
+	if d.GetLoginCookies()["steamcommunity.com"] == nil {
+		return nil, errors.New("steamcommunity.com cookie not found")
+	}
+
+	params := Param.Params{}
+	params.SetString("sessionid", d.GetLoginCookies()["steamcommunity.com"].SessionId)
+	params.SetString("buy_orderid", buyOrderID)
+
+	req, err := d.Request(http.MethodGet, Constants.GetBuyOrderStatus+"?"+params.ToUrl(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("referer", fmt.Sprintf("%s/market", Constants.CommunityOrigin))
+
+	resp, err := d.RetryRequest(Constants.Tries, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("查询订购单失败: %w", Errors.ErrRateLimited)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, Errors.ResponseError(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var response Model.BuyOrderStatusResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("解析订购单状态响应失败: %w", err)
+	}
+	if response.Success != 1 {
+		return nil, fmt.Errorf("%w: %s", Errors.ErrBuyOrderNotFound, buyOrderID)
+	}
+
+	return &Model.BuyOrderStatus{
+		BuyOrderID:        buyOrderID,
+		Active:            response.Active == 1,
+		Purchased:         response.Purchased,
+		Quantity:          response.Quantity,
+		QuantityRemaining: response.QuantityRemaining,
+		Purchases:         response.Purchases,
+	}, nil
+}
+
 type buyResult struct {
 	success          bool
 	needConfirmation bool
@@ -151,21 +435,17 @@ type buyResult struct {
 	error            error
 }
 
-func (d *Dao) buy(gameId string, creatorId string, name string, buyerPrice float64, sellerReceivePrice float64, confirmation string) buyResult {
-	Logger.Infof("[%s]购买[%s][%s][%.02f][%.02f][%s]", d.GetUsername(), creatorId, name, buyerPrice, sellerReceivePrice, confirmation)
-
-	buyerPriceStr := strconv.FormatFloat(buyerPrice*100, 'f', 0, 64)
-	sellerReceivePriceStr := strconv.FormatFloat(sellerReceivePrice*100, 'f', 0, 64)
-	feeStr := strconv.FormatFloat(buyerPrice*100-sellerReceivePrice*100, 'f', 0, 64)
+func (d *Dao) buy(gameId string, creatorId string, name string, fee Model.MarketFee, confirmation string) buyResult {
+	Logger.Infof("[%s]购买[%s][%s][total: %s][subtotal: %s][%s]", d.GetUsername(), creatorId, name, fee.Amount, fee.SellerReceive(), confirmation)
 
 	params := Param.Params{}
 	if d.GetLoginCookies()["steamcommunity.com"] != nil {
 		params.SetString("sessionid", d.GetLoginCookies()["steamcommunity.com"].SessionId)
 	}
-	params.SetString("currency", "23")
-	params.SetString("subtotal", sellerReceivePriceStr)
-	params.SetString("fee", feeStr)
-	params.SetString("total", buyerPriceStr)
+	params.SetInt64("currency", int64(fee.Amount.Currency))
+	params.SetInt64("subtotal", fee.SellerReceive().Amount)
+	params.SetInt64("fee", fee.Fees.Amount)
+	params.SetInt64("total", fee.Amount.Amount)
 	params.SetString("quantity", "1")
 	params.SetString("confirmation", confirmation)
 	params.SetInt64("save_my_address", 0)
@@ -298,6 +578,8 @@ func (d *Dao) buy(gameId string, creatorId string, name string, buyerPrice float
 		}
 
 		if buyListingResp.WalletInfo.Success == 1 {
+			// 缓存最新的钱包信息，后续计算手续费时使用
+			d.SetWalletInfo(buyListingResp.WalletInfo)
 			Logger.Infof("用户[%s]购买物品[creatorId: %s][confirmation: '%s']成功", d.GetUsername(), creatorId, confirmation)
 			return buyResult{
 				success:          true,
@@ -341,13 +623,17 @@ func (d *Dao) buy(gameId string, creatorId string, name string, buyerPrice float
 }
 
 // BuyListing 购买物品
-func (d *Dao) BuyListing(gameId, creatorId string, name string, buyerPrice float64, sellerReceivePrice float64, confirmation string, maFileContent string) error {
-	br := d.buy(gameId, creatorId, name, buyerPrice, sellerReceivePrice, confirmation)
+// buyerPrice 为买家支付价格，卖家到手金额和手续费按 Steam 的规则自动计算
+func (d *Dao) BuyListing(gameId, creatorId string, name string, buyerPrice Model.Money, confirmation string, maFileContent string) error {
+	appID, _ := strconv.Atoi(gameId)
+	fee := d.BuyerPriceToSellerReceive(appID, buyerPrice)
+
+	br := d.buy(gameId, creatorId, name, fee, confirmation)
 	if br.success && br.needConfirmation {
 		if err := d.ConfirmationForBuyList("allow", maFileContent); err != nil {
 			return err
 		}
-		brAgain := d.buy(gameId, creatorId, name, buyerPrice, sellerReceivePrice, br.confirmationId)
+		brAgain := d.buy(gameId, creatorId, name, fee, br.confirmationId)
 		if brAgain.success {
 			return nil
 		} else {
@@ -357,21 +643,21 @@ func (d *Dao) BuyListing(gameId, creatorId string, name string, buyerPrice float
 	return br.error
 }
 
-func (d *Dao) createOrder(gameId int, marketHashName string, price float64, quantity int64, confirmation string, maFileContent string) error {
+// createOrder 创建订购单，返回订购单ID
+// 需要手机令牌确认时先完成确认，再携带 confirmation_id 重新提交以获取订购单ID
+func (d *Dao) createOrder(gameId int, marketHashName string, price Model.Money, quantity int64, confirmation string, maFileContent string) (string, error) {
 	Logger.Infof("用户 [%s] 开始挂单，饰品名称: %s，数量：%d", d.GetUsername(), marketHashName, quantity)
 
 	var createOrderResp Model.CreateOrderResponse
 
-	priceTotalStr := strconv.FormatFloat(price*float64(quantity)*100, 'f', 0, 64)
-
 	params := Param.Params{}
 	if d.GetLoginCookies()["steamcommunity.com"] != nil {
 		params.SetString("sessionid", d.GetLoginCookies()["steamcommunity.com"].SessionId)
 	}
-	params.SetString("currency", "23")
+	params.SetInt64("currency", int64(price.Currency))
 	params.SetInt64("appid", int64(gameId))
 	params.SetString("market_hash_name", marketHashName)
-	params.SetString("price_total", priceTotalStr)
+	params.SetInt64("price_total", price.Mul(quantity).Amount)
 	params.SetInt64("tradefee_tax", 0)
 	params.SetInt64("quantity", quantity)
 	params.SetInt64("save_my_address", 0)
@@ -379,8 +665,7 @@ func (d *Dao) createOrder(gameId int, marketHashName string, price float64, quan
 
 	req, err := d.NewRequest(http.MethodPost, Constants.CreateOrder, strings.NewReader(params.Encode()))
 	if err != nil {
-
-		return err
+		return "", err
 	}
 
 	// 如果有会话信息，添加Cookie
@@ -390,12 +675,12 @@ func (d *Dao) createOrder(gameId int, marketHashName string, price float64, quan
 	}
 
 	req.Header.Add("origin", Constants.CommunityOrigin)
-	req.Header.Set("referer", fmt.Sprintf("%s/market/listings/440/%s", Constants.CommunityOrigin, marketHashName))
+	req.Header.Set("referer", fmt.Sprintf("%s/%d/%s", Constants.MarketListings, gameId, url.PathEscape(marketHashName)))
 
 	resp, err := d.RetryRequest(Constants.Tries, req)
 	if err != nil {
 		Logger.Errorf("[CreateOrder] 创建订单请求时 RetryRequest 失败: %v", err)
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 
@@ -406,7 +691,7 @@ func (d *Dao) createOrder(gameId int, marketHashName string, price float64, quan
 		gzReader, err := gzip.NewReader(resp.Body)
 		if err != nil {
 			Logger.Errorf("[CreateOrder] 创建订单请求时 NewReader 失败: %v", err)
-			return err
+			return "", err
 		}
 		defer gzReader.Close()
 		reader = gzReader
@@ -417,7 +702,7 @@ func (d *Dao) createOrder(gameId int, marketHashName string, price float64, quan
 	body, err := io.ReadAll(reader)
 	if err != nil {
 		Logger.Errorf("[CreateOrder] 创建订单请求时 ReadAll 失败: %v", err)
-		return err
+		return "", err
 	}
 
 	// type CreateOrderResponse struct {
@@ -430,242 +715,119 @@ func (d *Dao) createOrder(gameId int, marketHashName string, price float64, quan
 		Logger.Debugf("[CreateOrder] HTTP响应状态码: %d, HTTP响应内容: %s", resp.StatusCode, string(body))
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return "", fmt.Errorf("挂单失败: %w", Errors.ErrRateLimited)
+	}
+
 	if err := json.Unmarshal(body, &createOrderResp); err != nil {
 		Logger.Errorf("[CreateOrder] 创建订单请求时 Unmarshal 失败: %v", err)
-		return err
+		return "", err
 	}
 
 	Logger.Debugf("[CreateOrder] 创建订单响应: %+v", createOrderResp)
 
-	if resp.StatusCode == http.StatusNotAcceptable && createOrderResp.Success == 22 {
-		if createOrderResp.NeedConfirmation {
-			for i := range Constants.Tries {
-				Logger.Debugf("挂单需要手机令牌确认，第 %d 次尝试", i+1)
-				if err := d.ConfirmationForBuyListAndOrder("allow", maFileContent); err != nil {
-					if i == Constants.Tries-1 {
-						Logger.Errorf("[CreateOrder] 创建订单请求时 ConfirmationForBuyListAndOrder 失败: %v", err)
-						return err
-					}
-					continue
-				}
+	if createOrderResp.Success == 1 {
+		Logger.Infof("用户 [%s] 挂单成功，饰品名称: %s，订购单ID: %s", d.GetUsername(), marketHashName, createOrderResp.BuyOrderID)
+		return createOrderResp.BuyOrderID, nil
+	}
 
-				// if err := d.createOrder(marketHashName, price, quantity, createOrderResp.Confirmation["confirmation_id"], maFileContent); err != nil {
-				// 	Logger.Errorf("[CreateOrder] 创建订单请求时 createOrder 失败: %v", err)
-				// 	return err
-				// }
+	if resp.StatusCode == http.StatusNotAcceptable && createOrderResp.Success == 22 && createOrderResp.NeedConfirmation && confirmation == "" {
+		for i := range Constants.Tries {
+			Logger.Debugf("挂单需要手机令牌确认，第 %d 次尝试", i+1)
+			if err := d.ConfirmationForBuyListAndOrder("allow", maFileContent); err != nil {
+				if i == Constants.Tries-1 {
+					Logger.Errorf("[CreateOrder] 创建订单请求时 ConfirmationForBuyListAndOrder 失败: %v", err)
+					return "", err
+				}
+				continue
 			}
-
+			break
 		}
+
+		return d.createOrder(gameId, marketHashName, price, quantity, createOrderResp.Confirmation["confirmation_id"], maFileContent)
 	}
 
-	return nil
+	return "", fmt.Errorf("挂单失败，success=%d %s", createOrderResp.Success, createOrderResp.Message)
 }
 
-func (d *Dao) CreateOrder(marketHashName string, price float64, quantity int64, maFileContent string) error {
-	d.createOrder(570, marketHashName, price, quantity, "", maFileContent)
-	return nil
+// CreateOrder 为 Dota2 物品创建订购单
+func (d *Dao) CreateOrder(marketHashName string, price Model.Money, quantity int64, maFileContent string) error {
+	_, err := d.createOrder(Constants.Dota2, marketHashName, price, quantity, "", maFileContent)
+	return err
 }
 
-// GetInventory 获取用户库存
-func (d *Dao) GetSteamGift(gameId int, categoryId int) ([]Model.Item, error) {
-
-	inventoryUrl := fmt.Sprintf("%s/%d/%d/%d", Constants.GetInventory, d.GetSteamID(), gameId, categoryId)
-	req, err := d.Request(http.MethodGet, inventoryUrl, nil)
-	if err != nil {
-		return nil, fmt.Errorf("创建库存请求失败: %w", err)
-	}
-
-	resp, err := d.RetryRequest(Constants.Tries, req)
-	if err != nil {
-		return nil, fmt.Errorf("执行库存请求失败: %w", err)
-	}
-	defer resp.Body.Close()
+// PlaceBuyOrder 创建订购单并返回订购单ID，price 为单价
+func (d *Dao) PlaceBuyOrder(appID int, marketHashName string, price Model.Money, quantity int64, maFileContent string) (string, error) {
+	return d.createOrder(appID, marketHashName, price, quantity, "", maFileContent)
+}
 
-	body, err := io.ReadAll(resp.Body)
+// GetSteamGift 获取用户库存中的礼物
+func (d *Dao) GetSteamGift(gameId int, categoryId int) ([]Model.Item, error) {
+	inventoryItems, err := d.GetInventoryItems(gameId, categoryId)
 	if err != nil {
-		return nil, fmt.Errorf("读取库存响应失败: %w", err)
-	}
-
-	// 检查是否为GZIP压缩数据
-	if len(body) > 2 && body[0] == 0x1f && body[1] == 0x8b {
-		// 解压GZIP数据
-		reader, _ := gzip.NewReader(bytes.NewReader(body))
-		defer reader.Close()
-		body, _ = io.ReadAll(reader)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("获取库存失败,返回状态码: %d", resp.StatusCode)
-	}
-
-	var inventoryResponse Model.InventoryResponse
-	if err := json.Unmarshal(body, &inventoryResponse); err != nil {
-		return nil, fmt.Errorf("解析库存响应失败: %w", err)
-	}
-
-	if inventoryResponse.Success != 1 {
-		return nil, fmt.Errorf("库存API返回失败，success=%d", inventoryResponse.Success)
+		return nil, err
 	}
 
-	var steamGiftResponse []Model.Item
-	for _, asset := range inventoryResponse.Assets {
+	steamGiftResponse := make([]Model.Item, 0, len(inventoryItems))
+	for _, item := range inventoryItems {
 		steamGiftResponse = append(steamGiftResponse, Model.Item{
-			AssetID: asset.AssetID,
+			AssetID: item.AssetID,
 		})
 	}
 
 	return steamGiftResponse, nil
 }
 
-// GetInventory 获取用户库存
+// GetInventory 获取用户库存中可在市场出售的非商品物品
+// 需要完整库存或自定义过滤条件时使用 GetInventoryItems / NewInventoryIterator
 func (d *Dao) GetInventory(gameId int, categoryId int) ([]Model.Item, error) {
 	username := d.GetUsername()
 	Logger.Infof("开始获取用户 [%s] 的库存，游戏ID: %d, 分类ID: %d", username, gameId, categoryId)
 
-	inventoryUrl := fmt.Sprintf("%s/%d/%d/%d", Constants.GetInventory, d.GetSteamID(), gameId, categoryId)
-	req, err := d.NewRequest(http.MethodGet, inventoryUrl, nil)
+	inventoryItems, err := d.GetInventoryItems(gameId, categoryId, Model.InventoryMarketable, Model.InventoryNonCommodity)
 	if err != nil {
-		Logger.Errorf("创建库存请求失败，用户: [%s], 错误: %v", username, err)
-		return nil, fmt.Errorf("创建库存请求失败: %w", err)
-	}
-
-	resp, err := d.RetryRequest(Constants.Tries, req)
-	if err != nil {
-		Logger.Errorf("执行库存请求失败，用户: [%s], 错误: %v", username, err)
-		return nil, fmt.Errorf("执行库存请求失败: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		Logger.Errorf("读取库存响应失败，用户: [%s], 错误: %v", username, err)
-		return nil, fmt.Errorf("读取库存响应失败: %w", err)
-	}
-
-	// 检查是否为GZIP压缩数据
-	if len(body) > 2 && body[0] == 0x1f && body[1] == 0x8b {
-		// 解压GZIP数据
-		reader, _ := gzip.NewReader(bytes.NewReader(body))
-		defer reader.Close()
-		body, _ = io.ReadAll(reader)
-	}
-
-	Logger.Infof("库存响应状态码: %d", resp.StatusCode)
-	Logger.Infof("库存响应: %s", string(body))
-
-	switch resp.StatusCode {
-	case 429:
-		Logger.Warnf("用户 [%s] 获取库存遇到速率限制 (429)", username)
-		return nil, fmt.Errorf("获取库存失败: %w", Errors.ErrRateLimited)
-	case 401, 403:
-		Logger.Warnf("用户 [%s] 获取库存遇到授权失败 (401/403)", username)
-		return nil, fmt.Errorf("获取库存失败: %w", Errors.ErrAuthorizationFailed)
-	}
-
-	if resp.StatusCode == 403 {
-		return nil, fmt.Errorf("获取库存失败，错误码: %d", resp.StatusCode)
-	}
-
-	var inventoryResponse Model.InventoryResponse
-	if err := json.Unmarshal(body, &inventoryResponse); err != nil {
-		Logger.Errorf("解析库存响应失败，用户: [%s], 错误: %v", username, err)
-		return nil, fmt.Errorf("解析库存响应失败: %w", err)
+		Logger.Errorf("获取库存失败，用户: [%s], 错误: %v", username, err)
+		return nil, err
 	}
 
-	if inventoryResponse.Success != 1 {
-		Logger.Errorf("库存API返回失败，用户: [%s], success字段: %d", username, inventoryResponse.Success)
-		return nil, fmt.Errorf("库存API返回失败，success=%d", inventoryResponse.Success)
+	items := make([]Model.Item, 0, len(inventoryItems))
+	for _, item := range inventoryItems {
+		items = append(items, item.ToItem())
 	}
 
-	// 转换为内部物品模型
-	items := d.processInventoryData(&inventoryResponse, username)
-
 	Logger.Infof("获取用户 [%s] 的库存完成，共找到 %d 个可交易物品", username, len(items))
 
 	return items, nil
 }
 
-// processInventoryData 处理库存数据并返回可交易物品列表
-func (d *Dao) processInventoryData(inventoryResponse *Model.InventoryResponse, username string) []Model.Item {
-	// 边界检查
-	if inventoryResponse == nil {
-		Logger.Warnf("用户 [%s] 库存响应为空", username)
-		return []Model.Item{}
-	}
-
-	if len(inventoryResponse.Assets) == 0 {
-		Logger.Infof("用户 [%s] 的库存为空", username)
-		return []Model.Item{}
-	}
-
-	Logger.Debugf("开始处理用户 [%s] 的库存数据，资产数量: %d, 描述数量: %d",
-		username, len(inventoryResponse.Assets), len(inventoryResponse.Descriptions))
-
-	// 预分配 map 容量
-	descMap := make(map[string]Model.Description, len(inventoryResponse.Descriptions))
-
-	// 构建描述映射
-	for _, desc := range inventoryResponse.Descriptions {
-		// 直接字符串拼接，避免 fmt.Sprintf 的开销
-		key := desc.ClassID + "_" + desc.InstanceID
-		descMap[key] = desc
-	}
-
-	// 预估可交易物品数量，预分配切片容量
-	capacity := len(inventoryResponse.Assets)
-	if capacity > 100 {
-		capacity = capacity / 3 // 经验值：约1/3的物品可交易
+// PutList 上架物品，需要二次手机令牌确认
+// price 为买家支付价格，实际提交给 Steam 的卖家到手金额按手续费规则自动计算
+func (d *Dao) PutList(gameId int, contextId int, assetID string, price Model.Money, maFileContent string) (Model.MyListingReponse, error) {
+	sellResp, err := d.sellItem(gameId, contextId, assetID, 1, price)
+	if err != nil {
+		return Model.MyListingReponse{}, err
 	}
-	items := make([]Model.Item, 0, capacity)
-
-	// 统计计数器
-	var tradableCount, marketableCount, filteredCount, missingDescCount int
-
-	// 处理资产
-	for _, asset := range inventoryResponse.Assets {
-		key := asset.ClassID + "_" + asset.InstanceID
-		if desc, exists := descMap[key]; exists {
-			if desc.Tradable == 1 {
-				tradableCount++
-			}
-			if desc.Marketable == 1 {
-				marketableCount++
-			}
 
-			// 只包含可交易和可市场交易的物品
-			if desc.Marketable == 1 && desc.Commodity == 0 {
-				items = append(items, Model.Item{
-					AssetID:    asset.AssetID,
-					ClassID:    asset.ClassID,
-					InstanceID: asset.InstanceID,
-					Name:       desc.Name,
-					MarketName: desc.MarketName,
-					Tradable:   true,
-					Marketable: true,
-				})
-				filteredCount++
-			}
+	// 如果需要手机令牌确认
+	if sellResp.RequiresConfirmation == 1 && sellResp.NeedsMobileConfirmation {
+		Logger.Infof("物品上架需要手机令牌确认，assetID: %s", assetID)
+		result := d.ConfirmationForPutList("allow", maFileContent)
+		if !result.Success {
+			return Model.MyListingReponse{}, fmt.Errorf("上架确认失败: %s", assetID)
 		} else {
-			missingDescCount++
-			Logger.Debugf("用户 [%s] 的资产 %s 缺少描述信息", username, asset.AssetID)
+			return result.Result, nil
 		}
+	} else {
+		Logger.Warnf("无法进行确认操作，assetID: %s, RequiresConfirmation: %d", assetID, sellResp.RequiresConfirmation)
 	}
-
-	// 如果有大量缺失描述的物品，记录警告
-	if missingDescCount > len(inventoryResponse.Assets)/4 {
-		Logger.Warnf("用户 [%s] 有 %d 个物品缺少描述信息，占总数的 %.1f%%",
-			username, missingDescCount, float64(missingDescCount)*100/float64(len(inventoryResponse.Assets)))
-	}
-
-	Logger.Debugf("用户 [%s] 库存处理完成 - 总物品: %d, 可交易: %d, 可市场交易: %d, 筛选后: %d, 缺少描述: %d",
-		username, len(inventoryResponse.Assets), tradableCount, marketableCount, filteredCount, missingDescCount)
-
-	return items
+	return Model.MyListingReponse{}, nil
 }
 
-// PutList 上架物品，需要二次手机令牌确认
-func (d *Dao) PutList(gameId int, contextId int, assetID string, price float64, currency int, maFileContent string) (Model.MyListingReponse, error) {
-	Logger.Infof("用户 [%d] 上架物品，AssetID: %s, 价格: %.2f", d.GetSteamID(), assetID, price)
+// sellItem 提交上架请求，不处理手机令牌确认
+// price 为买家支付价格（单个物品），实际提交给 Steam 的卖家到手金额按手续费规则自动计算
+func (d *Dao) sellItem(gameId int, contextId int, assetID string, amount int64, price Model.Money) (*Model.PutListResponse, error) {
+	fee := d.BuyerPriceToSellerReceive(gameId, price)
+	Logger.Infof("用户 [%d] 上架物品，AssetID: %s, 价格: %s, 到手: %s", d.GetSteamID(), assetID, price, fee.SellerReceive())
 
 	data := url.Values{}
 	if d.GetLoginCookies()["steamcommunity.com"] != nil {
@@ -674,12 +836,12 @@ func (d *Dao) PutList(gameId int, contextId int, assetID string, price float64,
 	data.Set("appid", strconv.Itoa(gameId))
 	data.Set("contextid", strconv.Itoa(contextId)) // 分类
 	data.Set("assetid", assetID)
-	data.Set("amount", "1")
-	data.Set("price", strconv.FormatInt(int64(price*100), 10))
+	data.Set("amount", strconv.FormatInt(amount, 10))
+	data.Set("price", strconv.FormatInt(fee.SellerReceive().Amount, 10))
 
 	req, err := d.Request(http.MethodPost, Constants.PutList, strings.NewReader(data.Encode()))
 	if err != nil {
-		return Model.MyListingReponse{}, err
+		return nil, err
 	}
 
 	req.Header.Add("origin", Constants.CommunityOrigin)
@@ -687,13 +849,13 @@ func (d *Dao) PutList(gameId int, contextId int, assetID string, price float64,
 
 	resp, err := d.RetryRequest(Constants.Tries, req)
 	if err != nil {
-		return Model.MyListingReponse{}, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return Model.MyListingReponse{}, err
+		return nil, err
 	}
 
 	Logger.Debugf("[PutListing][%s]HTTP响应状态码: %d，响应内容: %s", assetID, resp.StatusCode, string(body))
@@ -701,44 +863,30 @@ func (d *Dao) PutList(gameId int, contextId int, assetID string, price float64,
 	// 检测429状态码（访问频繁）
 	if resp.StatusCode == http.StatusTooManyRequests {
 		Logger.Warnf("用户 [%d] 上架物品遇到速率限制 (429)", d.GetSteamID())
-		return Model.MyListingReponse{}, fmt.Errorf("上架失败: %w", Errors.ErrRateLimited)
+		return nil, fmt.Errorf("上架失败: %w", Errors.ErrRateLimited)
 	}
 
 	// 先行处理返回状态码不为200的情况
 	if resp.StatusCode != http.StatusOK {
-		return Model.MyListingReponse{}, fmt.Errorf("上架失败: %v", string(body))
+		return nil, fmt.Errorf("上架失败: %v", string(body))
 	}
 
 	var sellResp Model.PutListResponse
 	if err := json.Unmarshal(body, &sellResp); err != nil {
-		return Model.MyListingReponse{}, fmt.Errorf("解析上架响应失败: %w", err)
+		return nil, fmt.Errorf("解析上架响应失败: %w", err)
 	}
 
 	// 再行处理返回数据不为成功的情况
 	if !sellResp.Success {
 		switch sellResp.Message {
-		case "您的帐户当前无法使用社区市场。":
-		case "Your account is currently unable to use the Community Market.":
-			return Model.MyListingReponse{}, Errors.ErrAccountBan
+		case "您的帐户当前无法使用社区市场。", "Your account is currently unable to use the Community Market.":
+			return nil, Errors.ErrAccountBan
 		default:
-			return Model.MyListingReponse{}, fmt.Errorf("%s", sellResp.Message)
+			return nil, fmt.Errorf("%s", sellResp.Message)
 		}
-
 	}
 
-	// 如果需要手机令牌确认
-	if sellResp.RequiresConfirmation == 1 && sellResp.NeedsMobileConfirmation {
-		Logger.Infof("物品上架需要手机令牌确认，assetID: %s", assetID)
-		result := d.ConfirmationForPutList("allow", maFileContent)
-		if !result.Success {
-			return Model.MyListingReponse{}, fmt.Errorf("上架确认失败: %s", assetID)
-		} else {
-			return result.Result, nil
-		}
-	} else {
-		Logger.Warnf("无法进行确认操作，assetID: %s, RequiresConfirmation: %d", assetID, sellResp.RequiresConfirmation)
-	}
-	return Model.MyListingReponse{}, nil
+	return &sellResp, nil
 }
 
 func (d *Dao) ConfirmationForPutList(op string, maFileContent string) *Model.ConfirmationResult {
@@ -907,16 +1055,53 @@ func (d *Dao) GetConfirmations(maFileContent string) error {
 		return err
 	}
 
-	queryParams, err := Utils.GenerateConfirmationQueryParams(pt.MaFile.DeviceID, pt.MaFile.IdentitySecret, strconv.Itoa(int(pt.MaFile.Session.SteamID)), steamTime, "conf")
+	body, err := d.requestConfirmationList(pt, steamTime)
 	if err != nil {
-		Logger.Errorf("构建获取待确认请求参数失败，错误： %v", err)
+		Logger.Errorf("获取待确认列表失败，用户: [%s], 错误: %v", username, err)
 		return err
 	}
 
+	Logger.Infof("获取到的确认列表: %s", string(body))
+
+	// d.ConfirmationForPutList("allow", maFileContent)
+	// d.ConfirmationForBuyListAndOrder("allow", maFileContent)
+
+	return nil
+}
+
+// getConfirmations 获取手机令牌待确认列表
+func (d *Dao) getConfirmations(pt *Utils.PhoneToken) ([]Model.Confirmation, error) {
+	steamTime, err := d.GetSteamTimeLocal()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := d.requestConfirmationList(pt, steamTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var confirmResp Model.ConfirmationsResponse
+	if err := json.Unmarshal(body, &confirmResp); err != nil {
+		return nil, fmt.Errorf("解析待确认响应失败: %w", err)
+	}
+	if !confirmResp.Success {
+		return nil, fmt.Errorf("待确认API返回失败")
+	}
+
+	return confirmResp.Confirmations, nil
+}
+
+// requestConfirmationList 以手机客户端身份请求待确认列表，返回原始响应内容
+func (d *Dao) requestConfirmationList(pt *Utils.PhoneToken, steamTime int64) ([]byte, error) {
+	queryParams, err := pt.GenerateConfirmationQueryParams(steamTime, "conf")
+	if err != nil {
+		return nil, err
+	}
+
 	req, err := d.Request(http.MethodGet, Constants.GetConfirmationList+"?"+queryParams.ToUrl(), nil)
 	if err != nil {
-		Logger.Errorf("创建待确认请求失败，用户: [%s], 错误: %v", username, err)
-		return err
+		return nil, err
 	}
 
 	req.Header.Set("User-Agent", "Dalvik/2.1.0 (Linux; U; Android 9; Valve Steam App Version/3)")
@@ -925,23 +1110,15 @@ func (d *Dao) GetConfirmations(maFileContent string) error {
 
 	resp, err := d.RetryRequest(Constants.Tries, req)
 	if err != nil {
-		Logger.Errorf("执行待确认请求失败，用户: [%s], 错误: %v", username, err)
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		Logger.Errorf("读取待确认响应失败，用户: [%s], 错误: %v", username, err)
-		return err
+	if resp.StatusCode != http.StatusOK {
+		return nil, Errors.ResponseError(resp.StatusCode)
 	}
 
-	Logger.Infof("获取到的确认列表: %s", string(body))
-
-	// d.ConfirmationForPutList("allow", maFileContent)
-	// d.ConfirmationForBuyListAndOrder("allow", maFileContent)
-
-	return nil
+	return io.ReadAll(resp.Body)
 }
 
 func (d *Dao) ConfirmationForBuyList(op string, maFileContent string) error {
@@ -1176,7 +1353,7 @@ func (d *Dao) CancelSingleConfirmation(phoneToken *Utils.PhoneToken, conf Model.
 
 // 保留原有的正则表达式方法作为备用
 // 返回两个列表：已上架的物品和等待确认的物品
-func parseSteamMarketHTML(htmlContent string) (activeListings []Model.MyListingReponse, pendingListings []Model.MyListingReponse, err error) {
+func parseSteamMarketHTML(htmlContent string, currency Model.Currency) (activeListings []Model.MyListingReponse, pendingListings []Model.MyListingReponse, err error) {
 	// 首先尝试XPath方法
 	// activeItems, pendingItems, err := parseSteamMarketHTMLWithXPath(htmlContent)
 	// if err == nil && len(activeItems) > 0 {
@@ -1185,30 +1362,30 @@ func parseSteamMarketHTML(htmlContent string) (activeListings []Model.MyListingR
 	// }
 
 	// XPath方法失败时使用正则表达式方法（支持中英文）
-	return parseSteamMarketHTMLWithRegex(htmlContent)
+	return parseSteamMarketHTMLWithRegex(htmlContent, currency)
 }
 
 // parseSteamMarketHTMLWithRegex 使用正则表达式解析（支持中英文）
 // 返回两个列表：已上架的物品和等待确认的物品
-func parseSteamMarketHTMLWithRegex(htmlContent string) (activeListings []Model.MyListingReponse, pendingListings []Model.MyListingReponse, err error) {
+func parseSteamMarketHTMLWithRegex(htmlContent string, currency Model.Currency) (activeListings []Model.MyListingReponse, pendingListings []Model.MyListingReponse, err error) {
 	// 检测是否为中文版本
 	isChinese := strings.Contains(htmlContent, "我正在出售的物品") || strings.Contains(htmlContent, "这是买家所要支付")
 
 	// 解析已上架物品
-	activeListings = parseListingsFromSection(htmlContent, "tabContentsMyActiveMarketListingsRows", isChinese, true)
+	activeListings = parseListingsFromSection(htmlContent, "tabContentsMyActiveMarketListingsRows", isChinese, true, currency)
 	Logger.Infof("正则表达式方法共解析到 %d 个已上架物品", len(activeListings))
 
 	// 解析等待确认的物品
 	// 等待确认的物品通常紧跟在已上架物品后面，在同一个 table 中，但不在 tabContentsMyActiveMarketListingsRows 内
 	// 我们需要搜索包含 "My listings awaiting confirmation" 的区域
-	pendingListings = parseListingsFromHTMLByKeyword(htmlContent, "My listings awaiting confirmation", "我的等待确认的上架物品", isChinese, false)
+	pendingListings = parseListingsFromHTMLByKeyword(htmlContent, "My listings awaiting confirmation", "我的等待确认的上架物品", isChinese, false, currency)
 	Logger.Infof("正则表达式方法共解析到 %d 个等待确认物品", len(pendingListings))
 
 	return activeListings, pendingListings, nil
 }
 
 // parseListingsFromSection 从指定的section中解析物品列表
-func parseListingsFromSection(htmlContent string, sectionID string, isChinese bool, isActiveListing bool) []Model.MyListingReponse {
+func parseListingsFromSection(htmlContent string, sectionID string, isChinese bool, isActiveListing bool, currency Model.Currency) []Model.MyListingReponse {
 	var items []Model.MyListingReponse
 
 	// 提取指定区域的HTML内容
@@ -1311,12 +1488,13 @@ func parseListingsFromSection(htmlContent string, sectionID string, isChinese bo
 		}
 
 		// 提取物品名称（从market listings URL中获取）
-		nameRegex := regexp.MustCompile(`href="https://steamcommunity\.com/market/listings/\d+/([^"]+)"`)
-		if nameMatch := nameRegex.FindStringSubmatch(rowHTML); len(nameMatch) > 1 {
-			if decodedName, err := url.QueryUnescape(nameMatch[1]); err == nil {
+		nameRegex := regexp.MustCompile(`href="https://steamcommunity\.com/market/listings/(\d+)/([^"]+)"`)
+		if nameMatch := nameRegex.FindStringSubmatch(rowHTML); len(nameMatch) > 2 {
+			item.AppID, _ = strconv.Atoi(nameMatch[1])
+			if decodedName, err := url.QueryUnescape(nameMatch[2]); err == nil {
 				item.MarketHashName = strings.TrimSpace(decodedName)
 			} else {
-				item.MarketHashName = strings.TrimSpace(nameMatch[1])
+				item.MarketHashName = strings.TrimSpace(nameMatch[2])
 			}
 		}
 
@@ -1332,13 +1510,13 @@ func parseListingsFromSection(htmlContent string, sectionID string, isChinese bo
 			priceStr = strings.ReplaceAll(priceStr, "\\t", "")
 			priceStr = strings.ReplaceAll(priceStr, "\n", "")
 			priceStr = strings.ReplaceAll(priceStr, "\t", "")
-			item.BuyerPrice = parsePrice(strings.TrimSpace(priceStr))
+			item.BuyerPrice = parsePrice(strings.TrimSpace(priceStr), currency)
 		}
 
 		// 提取卖家到账价格
-		sellerPriceRegex := regexp.MustCompile(`\(¥\s*([^)]+)\)`)
+		sellerPriceRegex := regexp.MustCompile(`>\s*\(([^()<]*\d[^()<]*)\)\s*<`)
 		if sellerMatch := sellerPriceRegex.FindStringSubmatch(rowHTML); len(sellerMatch) > 1 {
-			item.SellerReceivePrice = parsePrice(sellerMatch[1])
+			item.SellerReceivePrice = parsePrice(sellerMatch[1], currency)
 		}
 
 		items = append(items, item)
@@ -1348,7 +1526,7 @@ func parseListingsFromSection(htmlContent string, sectionID string, isChinese bo
 }
 
 // parseListingsFromHTMLByKeyword 通过关键词在HTML中查找区域并解析物品列表
-func parseListingsFromHTMLByKeyword(htmlContent string, englishKeyword string, chineseKeyword string, isChinese bool, isActiveListing bool) []Model.MyListingReponse {
+func parseListingsFromHTMLByKeyword(htmlContent string, englishKeyword string, chineseKeyword string, isChinese bool, isActiveListing bool, currency Model.Currency) []Model.MyListingReponse {
 	var items []Model.MyListingReponse
 
 	// 根据语言选择关键词
@@ -1442,12 +1620,13 @@ func parseListingsFromHTMLByKeyword(htmlContent string, englishKeyword string, c
 		}
 
 		// 提取物品名称
-		nameRegex := regexp.MustCompile(`href="https://steamcommunity\.com/market/listings/\d+/([^"]+)"`)
-		if nameMatch := nameRegex.FindStringSubmatch(rowHTML); len(nameMatch) > 1 {
-			if decodedName, err := url.QueryUnescape(nameMatch[1]); err == nil {
+		nameRegex := regexp.MustCompile(`href="https://steamcommunity\.com/market/listings/(\d+)/([^"]+)"`)
+		if nameMatch := nameRegex.FindStringSubmatch(rowHTML); len(nameMatch) > 2 {
+			item.AppID, _ = strconv.Atoi(nameMatch[1])
+			if decodedName, err := url.QueryUnescape(nameMatch[2]); err == nil {
 				item.MarketHashName = strings.TrimSpace(decodedName)
 			} else {
-				item.MarketHashName = strings.TrimSpace(nameMatch[1])
+				item.MarketHashName = strings.TrimSpace(nameMatch[2])
 			}
 		}
 
@@ -1463,13 +1642,13 @@ func parseListingsFromHTMLByKeyword(htmlContent string, englishKeyword string, c
 			priceStr = strings.ReplaceAll(priceStr, "\\t", "")
 			priceStr = strings.ReplaceAll(priceStr, "\n", "")
 			priceStr = strings.ReplaceAll(priceStr, "\t", "")
-			item.BuyerPrice = parsePrice(strings.TrimSpace(priceStr))
+			item.BuyerPrice = parsePrice(strings.TrimSpace(priceStr), currency)
 		}
 
 		// 提取卖家到账价格
-		sellerPriceRegex := regexp.MustCompile(`\(¥\s*([^)]+)\)`)
+		sellerPriceRegex := regexp.MustCompile(`>\s*\(([^()<]*\d[^()<]*)\)\s*<`)
 		if sellerMatch := sellerPriceRegex.FindStringSubmatch(rowHTML); len(sellerMatch) > 1 {
-			item.SellerReceivePrice = parsePrice(sellerMatch[1])
+			item.SellerReceivePrice = parsePrice(sellerMatch[1], currency)
 		}
 
 		items = append(items, item)
@@ -1478,17 +1657,54 @@ func parseListingsFromHTMLByKeyword(htmlContent string, englishKeyword string, c
 	return items
 }
 
-// parsePrice 从价格字符串中提取数字部分并转换为float64
-func parsePrice(priceStr string) float64 {
-	// 移除所有非数字和小数点的字符
-	priceStr = strings.TrimSpace(priceStr)
-	// 使用正则表达式提取数字部分（包括小数点）
-	priceRegex := regexp.MustCompile(`(\d+\.?\d*)`)
-	matches := priceRegex.FindStringSubmatch(priceStr)
-	if len(matches) > 1 {
-		if price, err := strconv.ParseFloat(matches[1], 64); err == nil {
-			return price
+// parseBuyOrdersFromHTML 从上架列表HTML中解析进行中的订购单
+func parseBuyOrdersFromHTML(htmlContent string, currency Model.Currency) []Model.BuyOrder {
+	orders := make([]Model.BuyOrder, 0)
+
+	orderIDRegex := regexp.MustCompile(`id="mybuyorder_(\d+)"`)
+	orderIDMatches := orderIDRegex.FindAllStringSubmatchIndex(htmlContent, -1)
+
+	nameRegex := regexp.MustCompile(`href="https://steamcommunity\.com/market/listings/(\d+)/([^"]+)"`)
+	inlineRegex := regexp.MustCompile(`market_listing_inline_buyorder_qty">\s*(\d+)\s*@\s*</span>\s*([^<]+)<`)
+	qtyRegex := regexp.MustCompile(`market_listing_buyorder_qty">\s*<span[^>]*>\s*<span class="market_listing_price">\s*(\d+)\s*<`)
+
+	for i, match := range orderIDMatches {
+		endPos := len(htmlContent)
+		if i+1 < len(orderIDMatches) {
+			endPos = orderIDMatches[i+1][0]
+		}
+		rowHTML := htmlContent[match[0]:endPos]
+
+		order := Model.BuyOrder{
+			BuyOrderID: htmlContent[match[2]:match[3]],
 		}
+
+		if nameMatch := nameRegex.FindStringSubmatch(rowHTML); len(nameMatch) > 2 {
+			order.AppID, _ = strconv.Atoi(nameMatch[1])
+			if decodedName, err := url.PathUnescape(nameMatch[2]); err == nil {
+				order.MarketHashName = strings.TrimSpace(decodedName)
+			} else {
+				order.MarketHashName = strings.TrimSpace(nameMatch[2])
+			}
+		}
+
+		if inlineMatch := inlineRegex.FindStringSubmatch(rowHTML); len(inlineMatch) > 2 {
+			order.Quantity, _ = strconv.Atoi(inlineMatch[1])
+			order.Price = parsePrice(strings.TrimSpace(inlineMatch[2]), currency)
+		}
+		if order.Quantity == 0 {
+			if qtyMatch := qtyRegex.FindStringSubmatch(rowHTML); len(qtyMatch) > 1 {
+				order.Quantity, _ = strconv.Atoi(qtyMatch[1])
+			}
+		}
+
+		orders = append(orders, order)
 	}
-	return 0.0
+
+	return orders
+}
+
+// parsePrice 解析页面上的价格字符串，无法识别货币符号时使用 currency
+func parsePrice(priceStr string, currency Model.Currency) Model.Money {
+	return Model.MustParseMoney(strings.TrimSpace(priceStr), currency)
 }
@@ -0,0 +1,163 @@
+package Dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/JovanniChen/SteamDB/Steam/Constants"
+	"github.com/JovanniChen/SteamDB/Steam/Errors"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+	"github.com/JovanniChen/SteamDB/Steam/Param"
+)
+
+// itemNameIDCache 缓存 appid/market_hash_name 对应的 item_nameid，该值不会变化，可在账号间共享
+var itemNameIDCache sync.Map
+
+var itemNameIDRegexp = regexp.MustCompile(`Market_LoadOrderSpread\(\s*(\d+)\s*\)`)
+
+// GetItemNameID 从物品市场页面解析 item_nameid，查询订单簿时需要使用
+func (d *Dao) GetItemNameID(appID int, marketHashName string) (int64, error) {
+	key := strconv.Itoa(appID) + "/" + marketHashName
+	if id, ok := itemNameIDCache.Load(key); ok {
+		return id.(int64), nil
+	}
+
+	req, err := d.NewRequest(http.MethodGet, Constants.MarketListings+"/"+strconv.Itoa(appID)+"/"+url.PathEscape(marketHashName), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := d.RetryRequest(Constants.Tries, req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return 0, fmt.Errorf("获取物品市场页面失败: %w", Errors.ErrRateLimited)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, Errors.ResponseError(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	matches := itemNameIDRegexp.FindSubmatch(body)
+	if len(matches) < 2 {
+		return 0, fmt.Errorf("未能从市场页面解析到 item_nameid: %s", marketHashName)
+	}
+
+	id, err := strconv.ParseInt(string(matches[1]), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	itemNameIDCache.Store(key, id)
+	return id, nil
+}
+
+// GetOrderBook 获取物品的订单簿，价格使用当前钱包货币
+func (d *Dao) GetOrderBook(appID int, marketHashName string) (*Model.OrderBook, error) {
+	itemNameID, err := d.GetItemNameID(appID, marketHashName)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := d.walletCurrency()
+	country := d.GetCountryCode()
+	if country == "" {
+		country = "US"
+	}
+
+	params := Param.Params{}
+	params.SetString("country", country)
+	params.SetString("language", "english")
+	params.SetInt64("currency", int64(currency))
+	params.SetInt64("item_nameid", itemNameID)
+	params.SetString("two_factor", "0")
+	params.SetString("norender", "1")
+
+	req, err := d.NewRequest(http.MethodGet, Constants.ItemOrdersHistogram+"?"+params.ToUrl(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Referer", Constants.MarketListings+"/"+strconv.Itoa(appID)+"/"+url.PathEscape(marketHashName))
+
+	resp, err := d.RetryRequest(Constants.Tries, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("获取订单簿失败: %w", Errors.ErrRateLimited)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, Errors.ResponseError(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var histogram Model.ItemOrdersHistogramResponse
+	if err := json.Unmarshal(body, &histogram); err != nil {
+		return nil, fmt.Errorf("解析订单簿响应失败: %w", err)
+	}
+	if histogram.Success != 1 {
+		return nil, fmt.Errorf("获取订单簿失败: success=%d", histogram.Success)
+	}
+
+	return &Model.OrderBook{
+		AppID:          appID,
+		MarketHashName: marketHashName,
+		ItemNameID:     itemNameID,
+		LowestSell:     parseOrderBookPrice(histogram.LowestSellOrder, currency),
+		HighestBuy:     parseOrderBookPrice(histogram.HighestBuyOrder, currency),
+		SellOrders:     parseOrderBookGraph(histogram.SellOrderGraph, currency),
+		BuyOrders:      parseOrderBookGraph(histogram.BuyOrderGraph, currency),
+	}, nil
+}
+
+// parseOrderBookPrice 解析以最小货币单位表示的价格字符串
+func parseOrderBookPrice(s string, currency Model.Currency) Model.Money {
+	amount, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return Model.Money{Currency: currency}
+	}
+	return Model.Money{Amount: amount, Currency: currency}
+}
+
+// parseOrderBookGraph 将累计数量的价格图转换为每档价格的数量
+func parseOrderBookGraph(graph [][]any, currency Model.Currency) []Model.OrderBookLevel {
+	levels := make([]Model.OrderBookLevel, 0, len(graph))
+	var cumulative int64
+	for _, point := range graph {
+		if len(point) < 2 {
+			continue
+		}
+		price, ok1 := point[0].(float64)
+		total, ok2 := point[1].(float64)
+		if !ok1 || !ok2 {
+			continue
+		}
+
+		levels = append(levels, Model.OrderBookLevel{
+			Price:    Model.Money{Amount: int64(math.Round(price * 100)), Currency: currency},
+			Quantity: int64(total) - cumulative,
+		})
+		cumulative = int64(total)
+	}
+	return levels
+}
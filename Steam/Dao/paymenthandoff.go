@@ -0,0 +1,249 @@
+package Dao
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JovanniChen/SteamDB/Steam/Logger"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+	"github.com/JovanniChen/SteamDB/Steam/Utils"
+)
+
+const (
+	defaultPaymentHandoffAddr     = "127.0.0.1:0"
+	defaultPaymentHandoffTokenTTL = 15 * time.Minute
+)
+
+// GetPaymentHandoff 获取交易的外部支付表单，并尽量解析出可直接分享的支付跳转链接
+// 跳转链接解析失败时 RedirectURL 为空，仍可通过表单在其他设备上付款
+func (d *Dao) GetPaymentHandoff(transID string) (*Model.PaymentHandoff, error) {
+	formData, err := d.fetchPaymentForm(transID)
+	if err != nil {
+		return nil, err
+	}
+
+	handoff := &Model.PaymentHandoff{
+		TransID: transID,
+		Method:  formData.Method,
+		Action:  formData.Action,
+		Fields:  formData.Fields,
+	}
+
+	redirectURL, err := d.resolvePaymentRedirect(formData)
+	if err != nil {
+		Logger.Warnf("[%s]解析交易 %s 的支付跳转链接失败，使用支付表单: %v", d.GetUsername(), transID, err)
+		return handoff, nil
+	}
+	handoff.RedirectURL = redirectURL
+
+	return handoff, nil
+}
+
+// paymentHandoffEntry 等待操作员打开的支付交接信息
+type paymentHandoffEntry struct {
+	handoff   Model.PaymentHandoff
+	expiresAt time.Time
+}
+
+// PaymentHandoffServer 通过一次性链接分享支付交接信息的 HTTP 服务
+// 操作员在其他设备上打开链接后看到确认页，点击确认后跳转到支付页面；链接确认一次或过期后失效
+type PaymentHandoffServer struct {
+	options Model.PaymentHandoffServerOptions
+
+	mu       sync.Mutex
+	entries  map[string]paymentHandoffEntry
+	server   *http.Server
+	listener net.Listener
+}
+
+// NewPaymentHandoffServer 创建支付交接 HTTP 服务，需要调用 Start 开始监听
+func NewPaymentHandoffServer(opts *Model.PaymentHandoffServerOptions) *PaymentHandoffServer {
+	options := Model.PaymentHandoffServerOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.Addr == "" {
+		options.Addr = defaultPaymentHandoffAddr
+	}
+	if options.TokenTTL <= 0 {
+		options.TokenTTL = defaultPaymentHandoffTokenTTL
+	}
+	return &PaymentHandoffServer{
+		options: options,
+		entries: make(map[string]paymentHandoffEntry),
+	}
+}
+
+// Start 开始监听，重复调用无效果；监听所有网卡且未设置 PublicURL 时返回错误
+func (s *PaymentHandoffServer) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.server != nil {
+		return nil
+	}
+
+	// 监听所有网卡时无法得知操作员可访问的地址，生成的链接不可用
+	if s.options.PublicURL == "" && isWildcardAddr(s.options.Addr) {
+		return fmt.Errorf("支付交接服务监听所有网卡（%s）时需要设置 PublicURL", s.options.Addr)
+	}
+
+	listener, err := net.Listen("tcp", s.options.Addr)
+	if err != nil {
+		return fmt.Errorf("支付交接服务监听 %s 失败: %w", s.options.Addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /pay/{token}", s.handleConfirm)
+	mux.HandleFunc("POST /pay/{token}", s.handlePay)
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	s.listener = listener
+
+	go func(server *http.Server) {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			Logger.Errorf("支付交接服务异常退出: %v", err)
+		}
+	}(s.server)
+
+	return nil
+}
+
+// Stop 停止监听，未打开的链接全部失效
+func (s *PaymentHandoffServer) Stop() error {
+	s.mu.Lock()
+	server := s.server
+	s.server = nil
+	s.listener = nil
+	s.entries = make(map[string]paymentHandoffEntry)
+	s.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+	return server.Close()
+}
+
+// Publish 为支付交接信息生成一次性链接
+func (s *PaymentHandoffServer) Publish(handoff *Model.PaymentHandoff) (string, error) {
+	if handoff == nil || (handoff.RedirectURL == "" && handoff.Action == "") {
+		return "", errors.New("支付交接信息为空")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.server == nil {
+		return "", errors.New("支付交接服务未启动")
+	}
+
+	// 顺便清理过期的链接
+	now := time.Now()
+	for token, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, token)
+		}
+	}
+
+	token := Utils.SafeHexString(16)
+	s.entries[token] = paymentHandoffEntry{
+		handoff:   *handoff,
+		expiresAt: now.Add(s.options.TokenTTL),
+	}
+
+	return s.publicURL() + "/pay/" + token, nil
+}
+
+// publicURL 分享链接的地址前缀，调用方需持有 mu
+func (s *PaymentHandoffServer) publicURL() string {
+	if s.options.PublicURL != "" {
+		return strings.TrimRight(s.options.PublicURL, "/")
+	}
+	return "http://" + s.listener.Addr().String()
+}
+
+// isWildcardAddr 监听地址是否未指定主机（如 ":8089"、"0.0.0.0:8089"、"[::]:8089"）
+func isWildcardAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsUnspecified()
+}
+
+// paymentHandoffConfirmHTML 打开链接时显示的确认页，点击按钮提交 POST 后才使用链接
+const paymentHandoffConfirmHTML = `<!DOCTYPE html><html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><meta name="robots" content="noindex"><title>继续付款</title>` +
+	`<style>body { font-family: sans-serif; display: flex; justify-content: center; align-items: center; height: 100vh; margin: 0; } .container { text-align: center; } p { color: #666; } button { font-size: 1.2em; padding: 0.5em 2em; }</style>` +
+	`</head><body><div class="container"><h1>继续付款</h1><p>交易 %s</p>` +
+	`<form method="post"><button type="submit">前往支付页面</button></form>` +
+	`<p>链接只能使用一次</p></div></body></html>`
+
+// handleConfirm 返回确认页，不使用链接，避免聊天软件的链接预览提前使链接失效
+func (s *PaymentHandoffServer) handleConfirm(w http.ResponseWriter, r *http.Request) {
+	entry, ok := s.lookup(r.PathValue("token"), false)
+	if !ok {
+		http.Error(w, "链接无效或已过期", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := fmt.Fprintf(w, paymentHandoffConfirmHTML, html.EscapeString(entry.handoff.TransID)); err != nil {
+		Logger.Warnf("返回交易 %s 的付款确认页失败: %v", entry.handoff.TransID, err)
+	}
+}
+
+// handlePay 使用链接并跳转到支付页面，链接只能使用一次
+func (s *PaymentHandoffServer) handlePay(w http.ResponseWriter, r *http.Request) {
+	entry, ok := s.lookup(r.PathValue("token"), true)
+	if !ok {
+		http.Error(w, "链接无效或已过期", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	handoff := entry.handoff
+	if handoff.RedirectURL != "" {
+		http.Redirect(w, r, handoff.RedirectURL, http.StatusSeeOther)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := w.Write([]byte(Utils.AutoSubmitFormHTML(handoff.Action, handoff.Method, handoff.Fields))); err != nil {
+		Logger.Warnf("返回交易 %s 的支付表单失败: %v", handoff.TransID, err)
+	}
+}
+
+// lookup 查找未过期的链接，consume 为 true 时同时使其失效
+func (s *PaymentHandoffServer) lookup(token string, consume bool) (paymentHandoffEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[token]
+	if !ok {
+		return paymentHandoffEntry{}, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(s.entries, token)
+		return paymentHandoffEntry{}, false
+	}
+	if consume {
+		delete(s.entries, token)
+	}
+	return entry, true
+}
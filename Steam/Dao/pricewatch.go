@@ -0,0 +1,235 @@
+package Dao
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JovanniChen/SteamDB/Steam/Logger"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+)
+
+// defaultPriceWatchInterval 价格监控默认检查间隔
+const defaultPriceWatchInterval = time.Hour
+
+// PriceNotifier 价格监控事件通知器
+type PriceNotifier interface {
+	NotifyPriceEvent(event Model.PriceWatchEvent) error
+}
+
+// PriceNotifierFunc 使用函数作为价格监控事件通知器
+type PriceNotifierFunc func(event Model.PriceWatchEvent) error
+
+func (f PriceNotifierFunc) NotifyPriceEvent(event Model.PriceWatchEvent) error {
+	return f(event)
+}
+
+// PriceWatcher 定期查询物品在各地区的价格，保存到数据库并在打折、史低、限时免费时发送通知
+type PriceWatcher struct {
+	dao      *Dao
+	notifier PriceNotifier
+	options  Model.PriceWatcherOptions
+
+	mu      sync.Mutex
+	checkMu sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+	running bool
+}
+
+// NewPriceWatcher 创建价格监控
+func (d *Dao) NewPriceWatcher(notifier PriceNotifier, opts *Model.PriceWatcherOptions) *PriceWatcher {
+	options := Model.PriceWatcherOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.Interval <= 0 {
+		options.Interval = defaultPriceWatchInterval
+	}
+	return &PriceWatcher{
+		dao:      d,
+		notifier: notifier,
+		options:  options,
+	}
+}
+
+// Start 开始后台定期检查，重复调用无效果
+func (w *PriceWatcher) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.running {
+		return
+	}
+	w.running = true
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+
+	go w.loop(w.stop, w.done)
+}
+
+// Stop 停止检查并等待当前一轮结束
+func (w *PriceWatcher) Stop() {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.running = false
+	close(w.stop)
+	done := w.done
+	w.mu.Unlock()
+
+	<-done
+}
+
+func (w *PriceWatcher) loop(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Check(); err != nil {
+			Logger.Errorf("价格监控检查失败: %v", err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check 立即检查一次所有物品的价格，返回本次触发的事件
+// 价格与上一次记录不同时才写入数据库，写入成功后才发送通知，避免同一事件重复通知
+func (w *PriceWatcher) Check() ([]Model.PriceWatchEvent, error) {
+	w.checkMu.Lock()
+	defer w.checkMu.Unlock()
+
+	countries, err := w.countries()
+	if err != nil {
+		return nil, err
+	}
+
+	comparisons, err := w.dao.CompareStorePrices(w.options.Items, countries)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Model.PriceWatchEvent, 0)
+	now := time.Now()
+	for _, comparison := range comparisons {
+		for _, price := range comparison.Regions {
+			if !price.Available {
+				continue
+			}
+
+			previous, err := GetLatestStorePrice(comparison.Item, price.CountryCode)
+			if err != nil {
+				return events, err
+			}
+			previousLow, hasLow, err := GetLowestStorePrice(comparison.Item, price.CountryCode, price.Final.Currency)
+			if err != nil {
+				return events, err
+			}
+
+			changed := previous == nil || previous.Final != price.Final ||
+				previous.Original.Amount != price.Original.Amount || previous.DiscountPct != price.DiscountPct
+			if changed {
+				if err := SaveStorePrice(&Model.StorePriceRecord{
+					Item:        comparison.Item,
+					CountryCode: price.CountryCode,
+					Final:       price.Final,
+					Original:    price.Original,
+					DiscountPct: price.DiscountPct,
+					ObservedAt:  now,
+				}); err != nil {
+					return events, err
+				}
+			}
+
+			for _, eventType := range priceWatchEventTypes(price, previous, previousLow, hasLow) {
+				event := Model.PriceWatchEvent{
+					Type:        eventType,
+					Item:        comparison.Item,
+					Name:        comparison.Name,
+					Price:       price,
+					Previous:    previous,
+					PreviousLow: previousLow,
+				}
+				w.notify(event)
+				events = append(events, event)
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// countries 监控的地区代码，未设置时依次使用账号所在地区和钱包所在地区，都无法确定时返回错误
+func (w *PriceWatcher) countries() ([]string, error) {
+	countries := make([]string, 0, len(w.options.Countries))
+	for _, country := range w.options.Countries {
+		if country != "" {
+			countries = append(countries, country)
+		}
+	}
+	if len(countries) > 0 {
+		return countries, nil
+	}
+
+	country := w.dao.GetCountryCode()
+	if country == "" {
+		wallet, err := w.dao.FetchWalletInfo()
+		if err != nil {
+			return nil, fmt.Errorf("无法确定价格监控的地区，请设置 Countries: %w", err)
+		}
+		country = wallet.WalletCountry
+	}
+	if country == "" {
+		return nil, errors.New("无法确定价格监控的地区，请设置 Countries")
+	}
+	return []string{country}, nil
+}
+
+// priceWatchEventTypes 对比上一次记录和历史最低价，得到需要触发的事件
+func priceWatchEventTypes(price Model.RegionPrice, previous *Model.StorePriceRecord, previousLow Model.Money, hasLow bool) []Model.PriceWatchEventType {
+	types := make([]Model.PriceWatchEventType, 0)
+
+	// 原价不为零而现价为零，是限时免费领取，不再重复通知打折
+	if price.Final.IsZero() && !price.Original.IsZero() {
+		if previous == nil || !previous.Final.IsZero() {
+			types = append(types, Model.PriceWatchEventFreeToKeep)
+		}
+		return types
+	}
+
+	if price.DiscountPct > 0 && (previous == nil || previous.DiscountPct != price.DiscountPct) {
+		types = append(types, Model.PriceWatchEventNewDiscount)
+	}
+	if hasLow && previousLow.Currency == price.Final.Currency && price.Final.Amount < previousLow.Amount {
+		types = append(types, Model.PriceWatchEventHistoricalLow)
+	}
+
+	return types
+}
+
+func (w *PriceWatcher) notify(event Model.PriceWatchEvent) {
+	Logger.Infof("价格监控事件: %s，%s [%s] %s", event.Type, event.Name, event.Price.CountryCode, event.Price.Final)
+	if w.notifier == nil {
+		return
+	}
+
+	// 通知器异常不影响后续物品的处理
+	defer func() {
+		if r := recover(); r != nil {
+			Logger.Errorf("发送价格监控通知 %s 时发生异常: %v", event.Type, r)
+		}
+	}()
+	if err := w.notifier.NotifyPriceEvent(event); err != nil {
+		Logger.Errorf("发送价格监控通知 %s 失败: %v", event.Type, err)
+	}
+}
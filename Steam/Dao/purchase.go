@@ -0,0 +1,356 @@
+package Dao
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JovanniChen/SteamDB/Steam/Errors"
+	"github.com/JovanniChen/SteamDB/Steam/Logger"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+)
+
+const (
+	defaultPurchasePaymentTimeout = 15 * time.Minute
+	defaultPurchaseStatusInterval = 5 * time.Second
+	purchaseStatusRetries         = 3 // 超时后无法查询交易状态时的最多查询次数
+)
+
+// StartPurchase 创建并执行购买流程：清空购物车 → 加入商品 → 校验购物车 → 初始化交易 → 确认价格 → 付款 → 查询交易状态
+// 每一步完成后都会保存到数据库，程序中断后可调用 ResumePurchase 继续；任意一步失败时自动取消交易
+func (d *Dao) StartPurchase(items []Model.AddCartItem, opts *Model.PurchaseOptions) (*Model.Purchase, error) {
+	options := purchaseOptions(opts)
+	if options.PaymentMethod != Model.PaymentMethodAlipay && options.PaymentMethod != Model.PaymentMethodSteamWallet {
+		return nil, fmt.Errorf("购买流程不支持支付方式: %s", options.PaymentMethod)
+	}
+
+	now := time.Now()
+	purchase := &Model.Purchase{
+		State:         Model.PurchaseStateCreated,
+		PaymentMethod: options.PaymentMethod,
+		Items:         items,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := SavePurchase(d.GetSteamID(), purchase); err != nil {
+		return nil, err
+	}
+
+	return d.runPurchase(purchase, options)
+}
+
+// ResumePurchase 从数据库中保存的状态继续购买流程，已结束的流程直接返回
+// 尚未初始化交易的流程会从清空购物车重新开始；中断于钱包扣款（paying）的流程不会重新扣款，只查询交易状态
+// 等待付款的流程即使已超过付款时限，也会先查询交易状态，已付款时直接完成
+func (d *Dao) ResumePurchase(purchaseID int64, opts *Model.PurchaseOptions) (*Model.Purchase, error) {
+	purchase, err := GetPurchase(d.GetSteamID(), purchaseID)
+	if err != nil {
+		return nil, err
+	}
+	if purchase == nil {
+		return nil, fmt.Errorf("%w: %d", Errors.ErrPurchaseNotFound, purchaseID)
+	}
+	if purchase.State.IsFinal() {
+		return purchase, nil
+	}
+
+	options := purchaseOptions(opts)
+	options.PaymentMethod = purchase.PaymentMethod
+
+	// 购物车可能在中断期间被修改，交易初始化之前的步骤全部重做
+	if purchase.State == Model.PurchaseStateCartReady || purchase.State == Model.PurchaseStateCartValidated {
+		purchase.State = Model.PurchaseStateCreated
+	}
+
+	return d.runPurchase(purchase, options)
+}
+
+func purchaseOptions(opts *Model.PurchaseOptions) Model.PurchaseOptions {
+	options := Model.PurchaseOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.PaymentMethod == "" {
+		options.PaymentMethod = Model.PaymentMethodAlipay
+	}
+	if options.PaymentTimeout <= 0 {
+		options.PaymentTimeout = defaultPurchasePaymentTimeout
+	}
+	if options.StatusInterval <= 0 {
+		options.StatusInterval = defaultPurchaseStatusInterval
+	}
+	return options
+}
+
+// runPurchase 按当前状态依次执行剩余步骤，直到完成或失败
+func (d *Dao) runPurchase(purchase *Model.Purchase, options Model.PurchaseOptions) (*Model.Purchase, error) {
+	for !purchase.State.IsFinal() {
+		var next Model.PurchaseState
+		var err error
+
+		switch purchase.State {
+		case Model.PurchaseStateCreated:
+			next, err = d.purchasePrepareCart(purchase)
+		case Model.PurchaseStateCartReady:
+			next, err = d.purchaseValidateCart(purchase, options)
+		case Model.PurchaseStateCartValidated:
+			next, err = d.purchaseInitTransaction(purchase)
+		case Model.PurchaseStateTransactionNew:
+			next, err = d.purchaseConfirmPrice(purchase, options)
+		case Model.PurchaseStatePriceConfirmed:
+			next, err = d.purchasePay(purchase, options)
+		case Model.PurchaseStatePaying:
+			// 中断时扣款请求可能已提交，不能重新扣款或直接取消，先按交易状态确认结果
+			next = Model.PurchaseStatePaymentPending
+		case Model.PurchaseStatePaymentPending:
+			next, err = d.purchaseWaitPayment(purchase, options)
+		default:
+			err = fmt.Errorf("未知的购买状态: %s", purchase.State)
+		}
+
+		// 交易状态无法确认时可能已经付款，不取消交易，保持当前状态等待下次继续
+		if errors.Is(err, Errors.ErrPurchaseStatusUnknown) {
+			return purchase, err
+		}
+		if err != nil {
+			return purchase, d.failPurchase(purchase, options, err)
+		}
+		if err := d.setPurchaseState(purchase, next, options); err != nil {
+			return purchase, err
+		}
+	}
+
+	return purchase, nil
+}
+
+func (d *Dao) purchasePrepareCart(purchase *Model.Purchase) (Model.PurchaseState, error) {
+	if len(purchase.Items) == 0 {
+		return "", errors.New("没有要购买的商品")
+	}
+	if err := d.ClearCart(); err != nil {
+		return "", err
+	}
+	if err := d.AddItemToCart(purchase.Items); err != nil {
+		return "", err
+	}
+	return Model.PurchaseStateCartReady, nil
+}
+
+func (d *Dao) purchaseValidateCart(purchase *Model.Purchase, options Model.PurchaseOptions) (Model.PurchaseState, error) {
+	validation, err := d.ValidateCart()
+	if err != nil {
+		return "", err
+	}
+	if validation.Valid {
+		return Model.PurchaseStateCartValidated, nil
+	}
+
+	blocking := validation.BlockingItems()
+	if !options.DropInvalidItems {
+		return "", fmt.Errorf("%w: %s", Errors.ErrPurchaseCartInvalid, describeCartProblems(blocking))
+	}
+
+	remaining := removePurchaseItems(purchase.Items, blocking)
+	if len(remaining) == len(purchase.Items) {
+		// 校验失败的商品无法与购买商品对应，删除后也不会通过校验
+		return "", fmt.Errorf("%w: %s", Errors.ErrPurchaseCartInvalid, describeCartProblems(blocking))
+	}
+	if len(remaining) == 0 {
+		return "", fmt.Errorf("%w: 删除后购物车为空", Errors.ErrPurchaseCartInvalid)
+	}
+	if _, err := d.RemoveInvalidLineItems(validation); err != nil {
+		return "", err
+	}
+	purchase.Items = remaining
+	Logger.Warnf("[%s]购买流程 %d 已删除无法购买的商品: %s", d.GetUsername(), purchase.ID, describeCartProblems(blocking))
+
+	// 删除后保持 cart_ready 状态，下一轮重新校验
+	return Model.PurchaseStateCartReady, nil
+}
+
+func (d *Dao) purchaseInitTransaction(purchase *Model.Purchase) (Model.PurchaseState, error) {
+	transID, err := d.InitTransactionWithOptions(&Model.TransactionOptions{
+		PaymentMethod:      purchase.PaymentMethod,
+		SaveBillingAddress: true,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	purchase.TransID = transID
+	return Model.PurchaseStateTransactionNew, nil
+}
+
+func (d *Dao) purchaseConfirmPrice(purchase *Model.Purchase, options Model.PurchaseOptions) (Model.PurchaseState, error) {
+	total, err := d.GetFinalPrice(purchase.TransID)
+	if err != nil {
+		return "", err
+	}
+
+	purchase.Total = Model.NewMoney(int64(total), d.walletCurrency())
+	if !options.MaxTotal.IsZero() && options.MaxTotal.Currency != purchase.Total.Currency {
+		return "", fmt.Errorf("价格上限货币 %s 与最终价格货币 %s 不一致", options.MaxTotal.Currency, purchase.Total.Currency)
+	}
+	if !options.MaxTotal.IsZero() && purchase.Total.Amount > options.MaxTotal.Amount {
+		return "", fmt.Errorf("%w: 最终价格 %s，上限 %s", Errors.ErrPurchaseTotalExceeded, purchase.Total, options.MaxTotal)
+	}
+
+	// 钱包付款前检查余额，余额不足时直接失败并取消交易
+	if purchase.PaymentMethod == Model.PaymentMethodSteamWallet {
+		balance, err := d.walletBalance()
+		if err != nil {
+			return "", err
+		}
+		if err := checkWalletFunds(balance, purchase.Total); err != nil {
+			return "", err
+		}
+	}
+
+	return Model.PurchaseStatePriceConfirmed, nil
+}
+
+// purchasePay 发起付款：钱包付款先保存 paying 状态再扣款，外部支付获取支付链接
+// 扣款请求出错时结果未知（请求可能已被 Steam 处理），交由交易状态查询决定完成或失败
+func (d *Dao) purchasePay(purchase *Model.Purchase, options Model.PurchaseOptions) (Model.PurchaseState, error) {
+	if purchase.PaymentMethod == Model.PaymentMethodSteamWallet {
+		if err := d.setPurchaseState(purchase, Model.PurchaseStatePaying, options); err != nil {
+			return "", err
+		}
+		if err := d.FinalizeTransaction(purchase.TransID); err != nil {
+			Logger.Warnf("[%s]完成交易 %s 返回错误，按交易状态确认结果: %v", d.GetUsername(), purchase.TransID, err)
+		}
+		return Model.PurchaseStatePaymentPending, nil
+	}
+
+	paymentURL, err := d.GetAlipayURL(purchase.TransID)
+	if err != nil {
+		return "", err
+	}
+	purchase.PaymentURL = paymentURL
+	return Model.PurchaseStatePaymentPending, nil
+}
+
+// purchaseWaitPayment 轮询交易状态直到完成，从进入 payment_pending 起计算超时
+// 超时只在成功查询到一次交易状态之后才生效：继续超时的流程时先确认是否已在中断期间付款；
+// 超时后仍无法查询交易状态时返回 ErrPurchaseStatusUnknown，不取消交易
+func (d *Dao) purchaseWaitPayment(purchase *Model.Purchase, options Model.PurchaseOptions) (Model.PurchaseState, error) {
+	deadline := purchase.UpdatedAt.Add(options.PaymentTimeout)
+	checked := false
+	for count := 1; ; count++ {
+		status, err := d.GetTransactionStatus(purchase.TransID, count)
+		if err != nil {
+			Logger.Warnf("[%s]查询交易 %s 状态失败: %v", d.GetUsername(), purchase.TransID, err)
+		} else if status.Success == 1 {
+			purchase.Receipt = purchaseReceipt(purchase, status)
+			return Model.PurchaseStateCompleted, nil
+		} else if status.PurchaseResultDetail != 0 {
+			return "", &Errors.CheckoutError{Code: status.PurchaseResultDetail, Msg: Errors.GetCheckoutError(status.PurchaseResultDetail)}
+		} else {
+			checked = true
+		}
+
+		if time.Now().After(deadline) {
+			if checked {
+				return "", fmt.Errorf("%w: 交易 %s", Errors.ErrPurchasePaymentTimeout, purchase.TransID)
+			}
+			if count >= purchaseStatusRetries {
+				return "", fmt.Errorf("%w: 交易 %s: %w", Errors.ErrPurchaseStatusUnknown, purchase.TransID, err)
+			}
+		}
+		time.Sleep(options.StatusInterval)
+	}
+}
+
+// failPurchase 取消交易并将流程标记为失败
+func (d *Dao) failPurchase(purchase *Model.Purchase, options Model.PurchaseOptions, cause error) error {
+	Logger.Errorf("[%s]购买流程 %d 在 %s 阶段失败: %v", d.GetUsername(), purchase.ID, purchase.State, cause)
+
+	if purchase.TransID != "" {
+		if err := d.CancelTransaction(purchase.TransID); err != nil {
+			Logger.Warnf("[%s]取消交易 %s 失败: %v", d.GetUsername(), purchase.TransID, err)
+		}
+	}
+
+	purchase.Error = cause.Error()
+	if err := d.setPurchaseState(purchase, Model.PurchaseStateFailed, options); err != nil {
+		Logger.Errorf("保存购买流程 %d 失败: %v", purchase.ID, err)
+	}
+
+	return fmt.Errorf("%w: %w", Errors.ErrPurchaseFailed, cause)
+}
+
+func (d *Dao) setPurchaseState(purchase *Model.Purchase, state Model.PurchaseState, options Model.PurchaseOptions) error {
+	changed := purchase.State != state
+	purchase.State = state
+	purchase.UpdatedAt = time.Now()
+	if err := SavePurchase(d.GetSteamID(), purchase); err != nil {
+		return err
+	}
+
+	if changed && options.OnStateChange != nil {
+		options.OnStateChange(*purchase)
+	}
+	return nil
+}
+
+// purchaseReceipt 根据交易状态生成收据，收据中的金额为以分为单位的字符串
+func purchaseReceipt(purchase *Model.Purchase, status *Model.TransactionStatusResponse) *Model.PurchaseReceipt {
+	receipt := status.PurchaseReceipt
+	currency := Model.Currency(receipt.CurrencyCode)
+	if currency == 0 {
+		currency = purchase.Total.Currency
+	}
+	parse := func(s string) Model.Money {
+		amount, _ := strconv.ParseInt(s, 10, 64)
+		return Model.NewMoney(amount, currency)
+	}
+
+	return &Model.PurchaseReceipt{
+		PurchaseID:      purchase.ID,
+		TransID:         purchase.TransID,
+		PaymentMethod:   purchase.PaymentMethod,
+		Total:           purchase.Total,
+		FormattedTotal:  receipt.FormattedTotal,
+		BasePrice:       parse(receipt.BasePrice),
+		TotalDiscount:   parse(receipt.TotalDiscount),
+		Tax:             parse(receipt.Tax),
+		TransactionTime: time.Unix(receipt.TransactionTime, 0),
+		Items:           purchase.Items,
+	}
+}
+
+// removePurchaseItems 删除与校验失败商品对应的购买商品
+func removePurchaseItems(items []Model.AddCartItem, invalid []Model.CartItemValidation) []Model.AddCartItem {
+	remaining := make([]Model.AddCartItem, 0, len(items))
+	for _, item := range items {
+		drop := false
+		for _, v := range invalid {
+			if (item.BundleID != 0 && item.BundleID == v.BundleID) || (item.BundleID == 0 && item.PackageID != 0 && item.PackageID == v.PackageID) {
+				drop = true
+				break
+			}
+		}
+		if !drop {
+			remaining = append(remaining, item)
+		}
+	}
+	return remaining
+}
+
+func describeCartProblems(items []Model.CartItemValidation) string {
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		id := fmt.Sprintf("package %d", item.PackageID)
+		if item.BundleID != 0 {
+			id = fmt.Sprintf("bundle %d", item.BundleID)
+		}
+		problems := make([]string, 0, len(item.Problems))
+		for _, problem := range item.Problems {
+			problems = append(problems, string(problem))
+		}
+		parts = append(parts, id+": "+strings.Join(problems, ","))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,374 @@
+package Dao
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/JovanniChen/SteamDB/Steam/Constants"
+	"github.com/JovanniChen/SteamDB/Steam/Logger"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+)
+
+// defaultRepriceInterval 自动改价默认每轮间隔
+const defaultRepriceInterval = 10 * time.Minute
+
+const (
+	relistAssetRetries       = 5               // 下架后查找回到库存的物品的最多次数
+	relistAssetRetryInterval = 3 * time.Second // 每次查找之间的间隔
+)
+
+// RepriceStrategy 改价策略
+// relistsToday 为该物品过去 24 小时内的改价次数；返回 relist 为 false 时保持原价，reason 说明原因
+type RepriceStrategy interface {
+	Reprice(listing Model.MyListingReponse, book *Model.OrderBook, relistsToday int) (newPrice Model.Money, reason string, relist bool)
+}
+
+// UndercutStrategy 比市场最低价低 Undercut 的改价策略
+type UndercutStrategy struct {
+	Undercut         int64                  // 比最低价低多少（最小货币单位），默认 1
+	Floor            Model.Money            // 全局底价，不会改到低于该价格，货币须与上架货币一致
+	Floors           map[string]Model.Money // 按 market_hash_name 指定底价，优先于 Floor
+	MaxRelistsPerDay int                    // 每个物品 24 小时内最多改价次数，0 表示不限
+}
+
+// Reprice 实现 RepriceStrategy
+func (s *UndercutStrategy) Reprice(listing Model.MyListingReponse, book *Model.OrderBook, relistsToday int) (Model.Money, string, bool) {
+	lowest := book.LowestSell
+	if lowest.IsZero() {
+		return Model.Money{}, "市场没有其他在售", false
+	}
+	if lowest.Currency != listing.BuyerPrice.Currency {
+		return Model.Money{}, fmt.Sprintf("市场最低价货币 %s 与上架货币 %s 不一致", lowest.Currency, listing.BuyerPrice.Currency), false
+	}
+	if listing.BuyerPrice.Amount <= lowest.Amount {
+		return Model.Money{}, "当前价格已是最低价", false
+	}
+	if s.MaxRelistsPerDay > 0 && relistsToday >= s.MaxRelistsPerDay {
+		return Model.Money{}, fmt.Sprintf("今日改价次数已达上限 %d", s.MaxRelistsPerDay), false
+	}
+
+	undercut := s.Undercut
+	if undercut <= 0 {
+		undercut = 1
+	}
+	target := Model.Money{Amount: lowest.Amount - undercut, Currency: listing.BuyerPrice.Currency}
+
+	floor := s.Floor
+	if f, ok := s.Floors[listing.MarketHashName]; ok {
+		floor = f
+	}
+	if !floor.IsZero() && floor.Currency != listing.BuyerPrice.Currency {
+		return Model.Money{}, fmt.Sprintf("底价货币 %s 与上架货币 %s 不一致", floor.Currency, listing.BuyerPrice.Currency), false
+	}
+	if target.Amount < floor.Amount {
+		if listing.BuyerPrice.Amount <= floor.Amount {
+			return Model.Money{}, fmt.Sprintf("已达底价 %s", floor), false
+		}
+		target.Amount = floor.Amount
+	}
+	if target.Amount <= 0 || target.Amount >= listing.BuyerPrice.Amount {
+		return Model.Money{}, "无需改价", false
+	}
+
+	return target, fmt.Sprintf("最低价 %s 低于当前价格", lowest), true
+}
+
+// Repricer 定期检查上架物品，根据策略将被压价的物品以新价格重新上架
+type Repricer struct {
+	dao      *Dao
+	strategy RepriceStrategy
+	options  Model.RepricerOptions
+
+	mu      sync.Mutex
+	runMu   sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+	running bool
+}
+
+// NewRepricer 创建自动改价器
+func (d *Dao) NewRepricer(strategy RepriceStrategy, opts *Model.RepricerOptions) *Repricer {
+	options := Model.RepricerOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.Interval <= 0 {
+		options.Interval = defaultRepriceInterval
+	}
+	if options.RequestInterval <= 0 {
+		options.RequestInterval = Constants.ProcessInterval
+	}
+	return &Repricer{
+		dao:      d,
+		strategy: strategy,
+		options:  options,
+	}
+}
+
+// Start 开始后台定期改价，重复调用无效果
+func (r *Repricer) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running {
+		return
+	}
+	r.running = true
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+
+	go r.loop(r.stop, r.done)
+}
+
+// Stop 停止后台改价并等待当前一轮结束
+func (r *Repricer) Stop() {
+	r.mu.Lock()
+	if !r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = false
+	close(r.stop)
+	done := r.done
+	r.mu.Unlock()
+
+	<-done
+}
+
+func (r *Repricer) loop(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(r.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RunOnce(); err != nil {
+			Logger.Errorf("自动改价失败，用户: [%s], 错误: %v", r.dao.GetUsername(), err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce 立即执行一轮改价
+func (r *Repricer) RunOnce() (*Model.RepriceReport, error) {
+	r.runMu.Lock()
+	defer r.runMu.Unlock()
+
+	report := &Model.RepriceReport{
+		DryRun:    r.options.DryRun,
+		StartedAt: time.Now(),
+		Decisions: make([]Model.RepriceDecision, 0),
+	}
+
+	listings, err := r.dao.GetMyListings()
+	if err != nil {
+		return nil, err
+	}
+
+	steamID := r.dao.GetSteamID()
+	books := make(map[string]*Model.OrderBook)
+	// 下架前库存中已有的资产ID，按游戏和上下文在一轮中只获取一次
+	known := make(map[string]map[string]struct{})
+
+	// 同一物品我方的最低上架价，市场最低价就是我方上架时不再改价，避免自己的多个上架互相压价
+	ownLowest := make(map[string]Model.Money)
+	for _, listing := range listings.Active {
+		key := repriceKey(listing)
+		if own, ok := ownLowest[key]; !ok || listing.BuyerPrice.Amount < own.Amount {
+			ownLowest[key] = listing.BuyerPrice
+		}
+	}
+
+	for i, listing := range listings.Active {
+		if i > 0 {
+			time.Sleep(r.options.RequestInterval)
+		}
+
+		decision := r.decide(steamID, listing, books, ownLowest)
+		if decision.Action == Model.RepriceRelist {
+			ownLowest[repriceKey(listing)] = decision.NewPrice
+			if !r.options.DryRun {
+				newAssetID, delisted, err := r.relist(listing, decision.NewPrice, known)
+				decision.NewAssetID = newAssetID
+				if err != nil {
+					decision.Action = Model.RepriceFailed
+					if delisted {
+						decision.Action = Model.RepriceDelisted
+					}
+					decision.Reason = err.Error()
+				} else if err := SaveRepriceRecord(steamID, &decision, time.Now()); err != nil {
+					Logger.Errorf("保存改价记录失败: %v", err)
+				}
+			}
+		}
+
+		switch decision.Action {
+		case Model.RepriceRelist:
+			report.Relisted++
+		case Model.RepriceKeep:
+			report.Kept++
+		case Model.RepriceFailed:
+			report.Failed++
+		case Model.RepriceDelisted:
+			report.Delisted++
+		}
+		report.Decisions = append(report.Decisions, decision)
+	}
+
+	Logger.Infof("用户 [%s] 自动改价完成（DryRun: %t），改价: %d，保持: %d，失败: %d，已下架未上架: %d",
+		r.dao.GetUsername(), report.DryRun, report.Relisted, report.Kept, report.Failed, report.Delisted)
+
+	return report, nil
+}
+
+// decide 查询订单簿并根据策略得到改价决策，同一物品的订单簿在一轮中只查询一次
+func (r *Repricer) decide(steamID uint64, listing Model.MyListingReponse, books map[string]*Model.OrderBook, ownLowest map[string]Model.Money) Model.RepriceDecision {
+	decision := Model.RepriceDecision{
+		ListingID:      listing.ListingID,
+		AssetID:        listing.AssetID,
+		AppID:          listing.AppID,
+		MarketHashName: listing.MarketHashName,
+		OldPrice:       listing.BuyerPrice,
+		Action:         Model.RepriceKeep,
+	}
+
+	key := repriceKey(listing)
+	book, ok := books[key]
+	if !ok {
+		var err error
+		book, err = r.dao.GetOrderBook(listing.AppID, listing.MarketHashName)
+		if err != nil {
+			decision.Action = Model.RepriceFailed
+			decision.Reason = err.Error()
+			return decision
+		}
+		books[key] = book
+	}
+	decision.LowestSell = book.LowestSell
+
+	if own, ok := ownLowest[key]; ok && !book.LowestSell.IsZero() && own.Amount <= book.LowestSell.Amount {
+		decision.Reason = "市场最低价为我方上架"
+		return decision
+	}
+
+	relistsToday, err := CountRelistsSince(steamID, listing.AppID, listing.MarketHashName, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		decision.Action = Model.RepriceFailed
+		decision.Reason = err.Error()
+		return decision
+	}
+
+	newPrice, reason, relist := r.strategy.Reprice(listing, book, relistsToday)
+	decision.Reason = reason
+	if relist {
+		decision.Action = Model.RepriceRelist
+		decision.NewPrice = newPrice
+	}
+
+	return decision
+}
+
+func repriceKey(listing Model.MyListingReponse) string {
+	return fmt.Sprintf("%d/%s", listing.AppID, listing.MarketHashName)
+}
+
+// relist 下架后以新价格重新上架
+// 下架的物品会异步回到库存并获得新的资产ID，因此按下架前库存中已有的资产ID（known，一轮中共享）排除旧物品，
+// 下架后按 classid/instanceid/market_hash_name 查找新出现的资产再上架。
+// 返回新资产ID（找到时）以及物品是否已下架，已下架但未能重新上架时 delisted 为 true
+func (r *Repricer) relist(listing Model.MyListingReponse, price Model.Money, known map[string]map[string]struct{}) (newAssetID string, delisted bool, err error) {
+	contextID := r.contextID(listing)
+
+	key := fmt.Sprintf("%d/%d", listing.AppID, contextID)
+	existing, ok := known[key]
+	if !ok {
+		items, err := r.dao.GetInventoryItems(listing.AppID, contextID, Model.InventoryMarketable)
+		if err != nil {
+			return "", false, fmt.Errorf("获取库存失败: %w", err)
+		}
+		existing = make(map[string]struct{}, len(items))
+		for _, item := range items {
+			existing[item.AssetID] = struct{}{}
+		}
+		known[key] = existing
+	}
+	// 找到的资产无论是否重新上架成功都不能再被之后的物品认领
+	defer func() {
+		if newAssetID != "" {
+			existing[newAssetID] = struct{}{}
+		}
+	}()
+
+	if err := r.dao.RemoveMyListings(listing.ListingID); err != nil {
+		return "", false, fmt.Errorf("下架失败: %w", err)
+	}
+
+	for attempt := 0; attempt < relistAssetRetries; attempt++ {
+		time.Sleep(relistAssetRetryInterval)
+
+		returned, err := r.findReturnedAssets(listing, contextID, existing)
+		if err != nil {
+			Logger.Warnf("查找下架物品 %s 失败（第 %d 次）: %v", listing.MarketHashName, attempt+1, err)
+			continue
+		}
+		if len(returned) > 0 {
+			newAssetID = returned[0].AssetID
+			break
+		}
+	}
+	if newAssetID == "" {
+		return "", true, fmt.Errorf("已下架，但物品未在 %d 次查询内回到库存", relistAssetRetries)
+	}
+
+	if _, err := r.dao.PutList(listing.AppID, contextID, newAssetID, price, r.options.MaFileContent); err != nil {
+		return newAssetID, true, fmt.Errorf("已下架，重新上架失败: %w", err)
+	}
+
+	Logger.Infof("物品 %s 已改价: %s -> %s", listing.MarketHashName, listing.BuyerPrice, price)
+	return newAssetID, false, nil
+}
+
+// contextID 上架物品所在的库存上下文，优先使用上架列表返回的资产信息
+func (r *Repricer) contextID(listing Model.MyListingReponse) int {
+	if id, err := strconv.Atoi(listing.ContextID); err == nil && id > 0 {
+		return id
+	}
+	if id, ok := r.options.ContextIDs[listing.AppID]; ok {
+		return id
+	}
+	if listing.AppID == Constants.Steam {
+		return Constants.SteamCategory
+	}
+	return Constants.Dota2Catetory
+}
+
+// findReturnedAssets 在库存中查找与上架物品相同的可出售资产，跳过 known 中的资产ID
+// 上架列表带有类别ID和实例ID时要求完全一致，否则只按 market_hash_name 匹配
+func (r *Repricer) findReturnedAssets(listing Model.MyListingReponse, contextID int, known map[string]struct{}) ([]Model.InventoryItem, error) {
+	items, err := r.dao.GetInventoryItems(listing.AppID, contextID, Model.InventoryMarketable)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]Model.InventoryItem, 0)
+	for _, item := range items {
+		if _, ok := known[item.AssetID]; ok {
+			continue
+		}
+		if item.Description.MarketHashName != listing.MarketHashName {
+			continue
+		}
+		if listing.ClassID != "" && (item.ClassID != listing.ClassID || item.InstanceID != listing.InstanceID) {
+			continue
+		}
+		matched = append(matched, item)
+	}
+	return matched, nil
+}
@@ -0,0 +1,315 @@
+package Dao
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JovanniChen/SteamDB/Steam/Constants"
+	"github.com/JovanniChen/SteamDB/Steam/Errors"
+	"github.com/JovanniChen/SteamDB/Steam/Logger"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+	"github.com/JovanniChen/SteamDB/Steam/Utils"
+)
+
+const (
+	// marketListingConfirmationType 手机令牌确认列表中市场上架的类型
+	marketListingConfirmationType = 3
+
+	defaultSellConfirmBatchSize    = 30
+	defaultSellRateLimitBackoff    = 30 * time.Second
+	defaultSellMaxRateLimitRetries = 3
+)
+
+// SellItems 批量上架物品
+// 依次提交所有上架请求（遇到 429 时退避重试），全部提交后再统一批量确认本次提交的上架，返回每个物品的结果
+// maFileContent 为空时不进行确认，需要确认的物品状态为 needs_confirmation
+func (d *Dao) SellItems(orders []Model.SellOrder, opts *Model.SellOptions, maFileContent string) (*Model.SellReport, error) {
+	options := Model.SellOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.Interval <= 0 {
+		options.Interval = Constants.ProcessInterval
+	}
+	if options.RateLimitBackoff <= 0 {
+		options.RateLimitBackoff = defaultSellRateLimitBackoff
+	}
+	if options.MaxRateLimitRetries <= 0 {
+		options.MaxRateLimitRetries = defaultSellMaxRateLimitRetries
+	}
+	if options.ConfirmBatchSize <= 0 {
+		options.ConfirmBatchSize = defaultSellConfirmBatchSize
+	}
+
+	report := &Model.SellReport{Results: make([]Model.SellItemResult, len(orders))}
+	mobileIndexes := make([]int, 0)
+	var fatalErr error
+
+	for i, order := range orders {
+		result := &report.Results[i]
+		result.Order = order
+
+		if fatalErr != nil {
+			result.Status = Model.SellItemFailed
+			result.Reason = fatalErr.Error()
+			continue
+		}
+
+		price, err := d.sellOrderPrice(order, options.Pricer)
+		if err != nil {
+			result.Status = Model.SellItemFailed
+			result.Reason = err.Error()
+			continue
+		}
+		if price.IsZero() {
+			result.Status = Model.SellItemSkipped
+			continue
+		}
+		result.Price = price
+		result.SellerReceive = d.BuyerPriceToSellerReceive(order.AppID, price).SellerReceive()
+
+		if i > 0 {
+			time.Sleep(options.Interval)
+		}
+
+		sellResp, err := d.sellItemWithBackoff(order, price, options)
+		if err != nil {
+			Logger.Errorf("上架物品 %s 失败: %v", order.AssetID, err)
+			result.Status = Model.SellItemFailed
+			result.Reason = err.Error()
+			// 账号无法使用市场或持续限流时，后续物品无需再尝试
+			if errors.Is(err, Errors.ErrAccountBan) || Errors.IsRateLimitError(err) {
+				fatalErr = err
+			}
+			continue
+		}
+
+		if sellResp.RequiresConfirmation == 1 {
+			result.Status = Model.SellItemNeedsConfirmation
+			if sellResp.NeedsMobileConfirmation {
+				mobileIndexes = append(mobileIndexes, i)
+			}
+		} else {
+			result.Status = Model.SellItemListed
+		}
+	}
+
+	if len(mobileIndexes) > 0 && maFileContent != "" {
+		d.confirmSellResults(report, mobileIndexes, maFileContent, options.ConfirmBatchSize)
+	}
+
+	for _, result := range report.Results {
+		switch result.Status {
+		case Model.SellItemListed:
+			report.Listed++
+		case Model.SellItemNeedsConfirmation:
+			report.NeedsConfirmation++
+		case Model.SellItemFailed:
+			report.Failed++
+		case Model.SellItemSkipped:
+			report.Skipped++
+		}
+	}
+
+	Logger.Infof("用户 [%s] 批量上架完成，上架: %d，待确认: %d，失败: %d，跳过: %d",
+		d.GetUsername(), report.Listed, report.NeedsConfirmation, report.Failed, report.Skipped)
+
+	return report, nil
+}
+
+// sellOrderPrice 获取上架价格，订单未指定价格时调用定价回调
+func (d *Dao) sellOrderPrice(order Model.SellOrder, pricer Model.SellPricer) (Model.Money, error) {
+	if !order.Price.IsZero() {
+		return order.Price, nil
+	}
+	if pricer == nil {
+		return Model.Money{}, fmt.Errorf("未指定价格且没有定价回调")
+	}
+
+	price, err := pricer(order)
+	if err != nil {
+		return Model.Money{}, fmt.Errorf("定价失败: %w", err)
+	}
+	if price.Amount < 0 {
+		return Model.Money{}, fmt.Errorf("定价结果无效: %s", price)
+	}
+	return price, nil
+}
+
+// sellItemWithBackoff 提交上架请求，遇到 429 时按指数退避重试
+func (d *Dao) sellItemWithBackoff(order Model.SellOrder, price Model.Money, options Model.SellOptions) (*Model.PutListResponse, error) {
+	amount := order.Amount
+	if amount <= 0 {
+		amount = 1
+	}
+
+	backoff := options.RateLimitBackoff
+	for attempt := 0; ; attempt++ {
+		sellResp, err := d.sellItem(order.AppID, order.ContextID, order.AssetID, amount, price)
+		if err == nil || !Errors.IsRateLimitError(err) || attempt >= options.MaxRateLimitRetries {
+			return sellResp, err
+		}
+
+		Logger.Warnf("上架物品 %s 遇到速率限制，%s 后重试", order.AssetID, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// confirmSellResults 只确认本次提交的上架，并按各自的确认结果更新状态
+// 通过等待确认的上架列表得到物品对应的 listing ID，再与确认项的 creator_id 匹配
+func (d *Dao) confirmSellResults(report *Model.SellReport, indexes []int, maFileContent string, batchSize int) {
+	username := d.GetUsername()
+
+	// 无法确认时为所有待确认物品记录原因，保持 needs_confirmation 状态
+	failAll := func(reason string) {
+		for _, i := range indexes {
+			report.Results[i].Reason = reason
+		}
+	}
+
+	pt, err := Utils.LoadMaFile(maFileContent)
+	if err != nil {
+		Logger.Errorf("加载令牌文件失败，无法确认上架，用户: [%s], 错误: %v", username, err)
+		failAll(fmt.Sprintf("加载令牌文件失败: %v", err))
+		return
+	}
+
+	listings, err := d.GetMyListings()
+	if err != nil {
+		Logger.Errorf("获取等待确认的上架失败，用户: [%s], 错误: %v", username, err)
+		failAll(fmt.Sprintf("获取等待确认的上架失败: %v", err))
+		return
+	}
+	// 上架后物品获得新的资产ID，按上架前的资产ID（unowned_id）匹配提交的物品
+	listingIDs := make(map[string]string, len(listings.Pending))
+	for _, listing := range listings.Pending {
+		if listing.OriginalAssetID == "" {
+			continue
+		}
+		contextID, _ := strconv.Atoi(listing.OriginalContextID)
+		listingIDs[sellAssetKey(listing.AppID, contextID, listing.OriginalAssetID)] = listing.ListingID
+	}
+
+	confirmations, err := d.getConfirmations(pt)
+	if err != nil {
+		Logger.Errorf("获取待确认列表失败，用户: [%s], 错误: %v", username, err)
+		failAll(fmt.Sprintf("获取待确认列表失败: %v", err))
+		return
+	}
+	byListing := make(map[string]Model.Confirmation, len(confirmations))
+	for _, conf := range confirmations {
+		if conf.Type == marketListingConfirmationType {
+			byListing[conf.CreatorID] = conf
+		}
+	}
+
+	matched := make([]int, 0, len(indexes))
+	matchedConfs := make([]Model.Confirmation, 0, len(indexes))
+	for _, i := range indexes {
+		result := &report.Results[i]
+		listingID, ok := listingIDs[sellAssetKey(result.Order.AppID, result.Order.ContextID, result.Order.AssetID)]
+		if !ok {
+			result.Reason = "未找到等待确认的上架"
+			continue
+		}
+		conf, ok := byListing[listingID]
+		if !ok {
+			result.Reason = "未找到上架对应的确认项"
+			continue
+		}
+		matched = append(matched, i)
+		matchedConfs = append(matchedConfs, conf)
+	}
+
+	for start := 0; start < len(matched); start += batchSize {
+		end := min(start+batchSize, len(matched))
+
+		err := d.multiConfirm(pt, matchedConfs[start:end], "allow")
+		if err == nil {
+			for _, i := range matched[start:end] {
+				report.Results[i].Status = Model.SellItemListed
+			}
+			continue
+		}
+		Logger.Warnf("批量确认失败，改为逐个确认: %v", err)
+
+		for k := start; k < end; k++ {
+			result := &report.Results[matched[k]]
+			if err := d.AllowSingleConfirmation(pt, matchedConfs[k], 0); err != nil {
+				result.Reason = fmt.Sprintf("确认失败: %v", err)
+				continue
+			}
+			result.Status = Model.SellItemListed
+		}
+	}
+
+	if len(matched) < len(indexes) {
+		Logger.Warnf("部分上架未找到对应的确认项，用户: [%s], 提交: %d, 匹配: %d", username, len(indexes), len(matched))
+	}
+}
+
+// sellAssetKey 用于匹配上架物品的键
+func sellAssetKey(appID int, contextID int, assetID string) string {
+	return fmt.Sprintf("%d_%d_%s", appID, contextID, assetID)
+}
+
+// multiConfirm 一次请求处理多个确认项
+func (d *Dao) multiConfirm(pt *Utils.PhoneToken, confirmations []Model.Confirmation, op string) error {
+	steamTime, err := d.GetSteamTimeLocal()
+	if err != nil {
+		return err
+	}
+
+	params, err := pt.GenerateConfirmationQueryParams(steamTime, op)
+	if err != nil {
+		return err
+	}
+
+	data := url.Values{}
+	for k, v := range params {
+		data.Set(k, v)
+	}
+	data.Set("op", op)
+	for _, conf := range confirmations {
+		data.Add("cid[]", conf.ID)
+		data.Add("ck[]", conf.Nonce)
+	}
+
+	req, err := d.Request(http.MethodPost, Constants.MultiConfirmation, strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.RetryRequest(Constants.Tries, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Errors.ResponseError(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var confirmResp Model.ProcessConfirmationResponse
+	if err := json.Unmarshal(body, &confirmResp); err != nil {
+		return fmt.Errorf("解析批量确认响应失败: %w", err)
+	}
+	if !confirmResp.Success {
+		return fmt.Errorf("批量确认失败")
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package Dao
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/JovanniChen/SteamDB/Steam/Logger"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+)
+
+// RefreshInventorySnapshot 刷新库存快照并返回与上次快照的差异
+// 在可能的情况下跳过完整下载：
+//  1. 距上次快照不足 MinInterval 时直接跳过；
+//  2. 先只请求第一个物品，若物品总数和最新物品都与快照一致，且快照中没有等待解除限制的物品，则认为库存未变化。
+//     该检查看不到其他堆叠物品的数量变化，需要检测数量变化时应设置 Force 或定期强制刷新。
+//
+// steamID 为 0 时刷新当前登录账号
+func (d *Dao) RefreshInventorySnapshot(steamID uint64, appID int, contextID int, opts *Model.InventoryRefreshOptions) (*Model.InventoryDiff, error) {
+	if steamID == 0 {
+		steamID = d.GetSteamID()
+	}
+	if opts == nil {
+		opts = &Model.InventoryRefreshOptions{}
+	}
+
+	now := time.Now()
+
+	// 先只读取快照信息，未到刷新间隔时不需要加载所有物品
+	previous, err := GetInventorySnapshot(steamID, appID, contextID, false)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil && !opts.Force && opts.MinInterval > 0 && now.Sub(previous.FetchedAt) < opts.MinInterval {
+		return &Model.InventoryDiff{Skipped: true}, nil
+	}
+
+	if previous != nil {
+		previous, err = GetInventorySnapshot(steamID, appID, contextID, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if previous != nil && !opts.Force {
+		unchanged, err := d.probeInventoryUnchanged(steamID, appID, contextID, previous)
+		if err != nil {
+			return nil, err
+		}
+		if unchanged {
+			Logger.Debugf("库存 [%d/%d/%d] 未变化，跳过完整刷新", steamID, appID, contextID)
+			if err := TouchInventorySnapshot(steamID, appID, contextID, now); err != nil {
+				return nil, err
+			}
+			return &Model.InventoryDiff{Skipped: true}, nil
+		}
+	}
+
+	it := d.NewUserInventoryIterator(steamID, appID, contextID)
+	items, err := collectInventory(it)
+	if err != nil {
+		return nil, err
+	}
+
+	current := &Model.InventorySnapshot{
+		SteamID:    steamID,
+		AppID:      appID,
+		ContextID:  contextID,
+		TotalCount: it.TotalCount(),
+		Hash:       hashInventoryItems(items),
+		FetchedAt:  now,
+		Items:      items,
+	}
+
+	var diff Model.InventoryDiff
+	if previous == nil {
+		diff = Model.InventoryDiff{FirstSync: true, Added: items}
+	} else {
+		diff = DiffInventory(previous.Items, items)
+	}
+
+	// 内容完全一致时只更新时间，避免重写所有物品
+	if previous != nil && previous.Hash == current.Hash &&
+		len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0 {
+		if err := TouchInventorySnapshot(steamID, appID, contextID, now); err != nil {
+			return nil, err
+		}
+		return &diff, nil
+	}
+
+	if err := SaveInventorySnapshot(current); err != nil {
+		return nil, err
+	}
+
+	Logger.Infof("库存 [%d/%d/%d] 已刷新，新增: %d，移除: %d，变化: %d",
+		steamID, appID, contextID, len(diff.Added), len(diff.Removed), len(diff.Changed))
+
+	return &diff, nil
+}
+
+// probeInventoryUnchanged 只请求库存第一页的第一个物品，判断库存是否可能未变化
+// 只比较物品总数和第一个物品，其他堆叠物品的数量变化（总数不变时）无法发现
+func (d *Dao) probeInventoryUnchanged(steamID uint64, appID int, contextID int, previous *Model.InventorySnapshot) (bool, error) {
+	// 有物品处于临时交易/上架限制中时（owner_descriptions 中会给出解除时间），其状态会随时间变化，必须完整刷新
+	for _, item := range previous.Items {
+		if (!item.IsTradable() || !item.IsMarketable()) && len(item.Description.OwnerDescriptions) > 0 {
+			return false, nil
+		}
+	}
+
+	response, err := d.getInventoryPage(steamID, appID, contextID, "", 1)
+	if err != nil {
+		return false, err
+	}
+
+	if response.TotalInventoryCount != previous.TotalCount {
+		return false, nil
+	}
+
+	probe := mergeInventoryPage(response)
+	if len(probe) == 0 {
+		return len(previous.Items) == 0, nil
+	}
+
+	for _, item := range previous.Items {
+		if item.AssetID == probe[0].AssetID {
+			return len(inventoryItemChanges(item, probe[0])) == 0, nil
+		}
+	}
+
+	return false, nil
+}
+
+// DiffInventory 比较两次库存，返回新增、移除和状态变化的物品
+func DiffInventory(before []Model.InventoryItem, after []Model.InventoryItem) Model.InventoryDiff {
+	diff := Model.InventoryDiff{
+		Added:   make([]Model.InventoryItem, 0),
+		Removed: make([]Model.InventoryItem, 0),
+		Changed: make([]Model.InventoryItemChange, 0),
+	}
+
+	beforeMap := make(map[string]Model.InventoryItem, len(before))
+	for _, item := range before {
+		beforeMap[item.AssetID] = item
+	}
+
+	afterMap := make(map[string]struct{}, len(after))
+	for _, item := range after {
+		afterMap[item.AssetID] = struct{}{}
+
+		old, ok := beforeMap[item.AssetID]
+		if !ok {
+			diff.Added = append(diff.Added, item)
+			continue
+		}
+		if fields := inventoryItemChanges(old, item); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, Model.InventoryItemChange{
+				Before: old,
+				After:  item,
+				Fields: fields,
+			})
+		}
+	}
+
+	for _, item := range before {
+		if _, ok := afterMap[item.AssetID]; !ok {
+			diff.Removed = append(diff.Removed, item)
+		}
+	}
+
+	return diff
+}
+
+// inventoryItemChanges 返回同一资产在两次快照间变化的字段
+func inventoryItemChanges(before Model.InventoryItem, after Model.InventoryItem) []string {
+	fields := make([]string, 0)
+	if before.Amount != after.Amount {
+		fields = append(fields, "amount")
+	}
+	if before.IsTradable() != after.IsTradable() {
+		fields = append(fields, "tradable")
+	}
+	if before.IsMarketable() != after.IsMarketable() {
+		fields = append(fields, "marketable")
+	}
+	if before.ClassID != after.ClassID || before.InstanceID != after.InstanceID {
+		fields = append(fields, "classid")
+	}
+	if before.Description.MarketHashName != after.Description.MarketHashName {
+		fields = append(fields, "market_hash_name")
+	}
+	if len(before.Description.FraudWarnings) != len(after.Description.FraudWarnings) {
+		fields = append(fields, "fraudwarnings")
+	}
+	return fields
+}
+
+// hashInventoryItems 计算库存内容摘要，与物品顺序无关
+// 包含 inventoryItemChanges 比较的所有字段，摘要相同时两次库存不会有差异
+func hashInventoryItems(items []Model.InventoryItem) string {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, item.AssetID+"|"+item.ClassID+"|"+item.InstanceID+"|"+
+			strconv.FormatInt(item.Amount, 10)+"|"+
+			strconv.FormatBool(item.IsTradable())+"|"+strconv.FormatBool(item.IsMarketable())+"|"+
+			item.Description.MarketHashName+"|"+strconv.Itoa(len(item.Description.FraudWarnings)))
+	}
+	sort.Strings(keys)
+
+	h := sha1.New()
+	for _, key := range keys {
+		h.Write([]byte(key))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,280 @@
+package Dao
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/JovanniChen/SteamDB/Steam/Constants"
+	"github.com/JovanniChen/SteamDB/Steam/Errors"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+	"github.com/JovanniChen/SteamDB/Steam/Param"
+	"github.com/JovanniChen/SteamDB/Steam/Protoc"
+	"google.golang.org/protobuf/proto"
+)
+
+// storeItemsBatchSize IStoreBrowseService/GetItems 单次请求的物品数量上限
+const storeItemsBatchSize = 100
+
+// defaultStoreDataRequest 未指定 dataRequest 时请求的数据
+func defaultStoreDataRequest() *Protoc.ItemDataRequest {
+	return &Protoc.ItemDataRequest{
+		IncludeAssets:             true,
+		IncludeRelease:            true,
+		IncludePlatforms:          true,
+		IncludeAllPurchaseOptions: true,
+		IncludeReviews:            true,
+		IncludeBasicInfo:          true,
+		IncludeTagCount:           20,
+	}
+}
+
+// GetStoreItems 批量获取商店物品（游戏、礼包、捆绑包）详情
+// country 为空时使用账号所在地区，language 为空时使用账号语言，dataRequest 为空时请求常用数据
+// 不需要登录，登录后会返回与账号相关的购买选项
+func (d *Dao) GetStoreItems(ids []Model.StoreItemID, country, language string, dataRequest *Protoc.ItemDataRequest) (*Model.StoreItems, error) {
+	return d.storeItems(ids, country, language, dataRequest, false)
+}
+
+// storeItems 分批查询商店物品，anonymous 为 true 时不携带访问令牌，返回该地区的公开价格
+func (d *Dao) storeItems(ids []Model.StoreItemID, country, language string, dataRequest *Protoc.ItemDataRequest, anonymous bool) (*Model.StoreItems, error) {
+	if country == "" {
+		country = d.GetCountryCode()
+	}
+	if country == "" {
+		country = "US"
+	}
+	if language == "" {
+		language = d.GetLanguage()
+	}
+	if language == "" {
+		language = "english"
+	}
+	if dataRequest == nil {
+		dataRequest = defaultStoreDataRequest()
+	}
+
+	result := &Model.StoreItems{
+		Apps:     make([]*Protoc.StoreItem, 0),
+		Packages: make([]*Protoc.StoreItem, 0),
+		Bundles:  make([]*Protoc.StoreItem, 0),
+		Failed:   make([]Model.StoreItemID, 0),
+	}
+
+	for start := 0; start < len(ids); start += storeItemsBatchSize {
+		end := min(start+storeItemsBatchSize, len(ids))
+
+		store, err := d.getStoreItems(ids[start:end], country, language, dataRequest, anonymous)
+		if err != nil {
+			return nil, err
+		}
+
+		returned := make(map[Model.StoreItemID]bool, len(store.StoreItems))
+		for _, item := range store.StoreItems {
+			id := storeItemID(item)
+			returned[id] = true
+
+			if item.Success != 1 {
+				result.Failed = append(result.Failed, id)
+				continue
+			}
+
+			switch Model.StoreItemType(item.ItemType) {
+			case Model.StoreItemTypeApp:
+				result.Apps = append(result.Apps, item)
+			case Model.StoreItemTypePackage:
+				result.Packages = append(result.Packages, item)
+			case Model.StoreItemTypeBundle:
+				result.Bundles = append(result.Bundles, item)
+			}
+		}
+
+		// 服务器未返回的物品同样视为失败
+		for _, id := range ids[start:end] {
+			if !returned[id] {
+				result.Failed = append(result.Failed, id)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (d *Dao) getStoreItems(ids []Model.StoreItemID, country, language string, dataRequest *Protoc.ItemDataRequest, anonymous bool) (*Protoc.Store, error) {
+	itemIDs := make([]*Protoc.ItemID, 0, len(ids))
+	for _, id := range ids {
+		itemIDs = append(itemIDs, &Protoc.ItemID{
+			Appid:     id.AppID,
+			Packageid: id.PackageID,
+			Bundleid:  id.BundleID,
+		})
+	}
+
+	storeInfo := &Protoc.StoreInfo{
+		Ids: itemIDs,
+		Context: &Protoc.Context{
+			Language:    language,
+			CountryCode: country,
+			SteamRealm:  1,
+		},
+		DataRequest: dataRequest,
+	}
+
+	// 序列化为protobuf格式
+	data, err := proto.Marshal(storeInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	params := Param.Params{}
+	if !anonymous {
+		if accessToken, err := d.AccessToken(); err == nil {
+			params.SetString("access_token", accessToken)
+		}
+	}
+	params.SetString("input_protobuf_encoded", base64.StdEncoding.EncodeToString(data))
+
+	req, err := d.NewRequest(http.MethodGet, Constants.GetStoreItems+"?"+params.ToUrl(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.RetryRequest(Constants.Tries, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("获取商店物品失败: %w", Errors.ErrRateLimited)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, Errors.ResponseError(resp.StatusCode)
+	}
+
+	// 读取响应数据
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return nil, err
+	}
+
+	// 空的物品列表会返回空响应
+	store := &Protoc.Store{}
+	if buf.Len() == 0 {
+		return store, nil
+	}
+	if err := protoUnmarshalWithRetry(buf.Bytes(), store, "GetStoreItems", 3); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// storeItemID 根据返回物品的类型得到对应的物品ID
+func storeItemID(item *Protoc.StoreItem) Model.StoreItemID {
+	return Model.NewStoreItemID(Model.StoreItemType(item.ItemType), item.Id)
+}
+
+// CompareStorePrices 匿名查询物品在多个地区的价格，返回每个物品的地区价格对比
+// 价格使用各地区的商店货币，不做汇率换算
+func (d *Dao) CompareStorePrices(ids []Model.StoreItemID, countries []string) ([]Model.PriceComparison, error) {
+	comparisons := make([]Model.PriceComparison, len(ids))
+	for i, id := range ids {
+		comparisons[i] = Model.PriceComparison{
+			Item:    id,
+			Regions: make([]Model.RegionPrice, 0, len(countries)),
+		}
+	}
+
+	dataRequest := &Protoc.ItemDataRequest{IncludeAllPurchaseOptions: true}
+	for _, country := range countries {
+		country = strings.ToUpper(country)
+		items, err := d.storeItems(ids, country, "english", dataRequest, true)
+		if err != nil {
+			return nil, fmt.Errorf("查询地区 %s 价格失败: %w", country, err)
+		}
+
+		found := make(map[Model.StoreItemID]*Protoc.StoreItem)
+		for _, list := range [][]*Protoc.StoreItem{items.Apps, items.Packages, items.Bundles} {
+			for _, item := range list {
+				found[storeItemID(item)] = item
+			}
+		}
+
+		for i := range comparisons {
+			comparison := &comparisons[i]
+			item := found[comparison.Item]
+			if item != nil && comparison.Name == "" {
+				comparison.Name = item.Name
+			}
+			comparison.Regions = append(comparison.Regions, regionPrice(country, comparison.Item, item))
+		}
+	}
+
+	return comparisons, nil
+}
+
+// regionPrice 从物品的购买选项中取出价格，礼包和捆绑包取与自身ID对应的选项，游戏取最佳购买选项
+func regionPrice(country string, id Model.StoreItemID, item *Protoc.StoreItem) Model.RegionPrice {
+	price := Model.RegionPrice{CountryCode: country}
+	if item == nil {
+		return price
+	}
+
+	var option *Protoc.Option
+	switch {
+	case id.PackageID != 0:
+		for _, o := range item.PurchaseOptions {
+			if uint32(o.Packageid) == id.PackageID {
+				option = o
+				break
+			}
+		}
+	case id.BundleID != 0:
+		for _, o := range item.PurchaseOptions {
+			if uint32(o.Bundleid) == id.BundleID {
+				option = o
+				break
+			}
+		}
+	}
+	if option == nil {
+		option = item.BestPurchaseOption
+	}
+	if option == nil {
+		return price
+	}
+
+	currency := optionCurrency(country, option)
+	original := option.OriginalPriceInCents
+	if original == 0 {
+		original = option.FinalPriceInCents
+	}
+
+	price.Available = true
+	price.Final = Model.NewMoney(option.FinalPriceInCents, currency)
+	price.Original = Model.NewMoney(original, currency)
+	price.DiscountPct = option.DiscountPct
+	price.PurchaseOptionName = option.PurchaseOptionName
+	price.PackageID = option.Packageid
+	price.BundleID = option.Bundleid
+	return price
+}
+
+// optionCurrency 从购买选项的格式化价格中识别货币，无法识别时按国家推断
+// 同一国家可能使用不同的商店货币（如部分地区以美元定价），以 Steam 返回的价格字符串为准
+func optionCurrency(country string, option *Protoc.Option) Model.Currency {
+	fallback := Model.CurrencyForCountry(country)
+	for _, formatted := range []string{option.FormattedFinalPrice, option.FormattedOriginalPrice} {
+		if formatted == "" {
+			continue
+		}
+		money, err := Model.ParseMoney(formatted, fallback)
+		if err == nil && money.Currency != Model.CurrencyUnknown {
+			return money.Currency
+		}
+	}
+	return fallback
+}
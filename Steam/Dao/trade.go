@@ -0,0 +1,503 @@
+package Dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JovanniChen/SteamDB/Steam/Constants"
+	"github.com/JovanniChen/SteamDB/Steam/Errors"
+	"github.com/JovanniChen/SteamDB/Steam/Logger"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+	"github.com/JovanniChen/SteamDB/Steam/Param"
+	"github.com/JovanniChen/SteamDB/Steam/Utils"
+)
+
+// tradeOfferConfirmationType 手机令牌确认列表中交易报价的类型
+const tradeOfferConfirmationType = 2
+
+// ParseTradeURL 解析交易链接，例如 https://steamcommunity.com/tradeoffer/new/?partner=12345678&token=AbCdEfGh
+func ParseTradeURL(tradeURL string) (*Model.TradeURL, error) {
+	u, err := url.Parse(strings.TrimSpace(tradeURL))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", Errors.ErrInvalidTradeURL, err)
+	}
+	if u.Scheme != "https" || u.Host != Constants.Domain.Community || !strings.HasPrefix(u.Path, "/tradeoffer/new") {
+		return nil, Errors.ErrInvalidTradeURL
+	}
+
+	partner, err := strconv.ParseUint(u.Query().Get("partner"), 10, 32)
+	if err != nil || partner == 0 {
+		return nil, Errors.ErrInvalidTradeURL
+	}
+
+	return &Model.TradeURL{
+		Partner: uint32(partner),
+		Token:   u.Query().Get("token"),
+	}, nil
+}
+
+// tradeOfferAssets json_tradeoffer 中一方的物品
+type tradeOfferAssets struct {
+	Assets   []tradeOfferAsset `json:"assets"`
+	Currency []any             `json:"currency"`
+	Ready    bool              `json:"ready"`
+}
+
+type tradeOfferAsset struct {
+	AppID     int    `json:"appid"`
+	ContextID string `json:"contextid"`
+	Amount    int64  `json:"amount"`
+	AssetID   string `json:"assetid"`
+}
+
+func newTradeOfferAssets(items []Model.TradeOfferItem) tradeOfferAssets {
+	assets := make([]tradeOfferAsset, 0, len(items))
+	for _, item := range items {
+		amount := item.Amount
+		if amount <= 0 {
+			amount = 1
+		}
+		assets = append(assets, tradeOfferAsset{
+			AppID:     item.AppID,
+			ContextID: item.ContextID,
+			Amount:    amount,
+			AssetID:   item.AssetID,
+		})
+	}
+	return tradeOfferAssets{Assets: assets, Currency: []any{}}
+}
+
+// SendTradeOffer 通过交易链接向对方发送报价
+// itemsToGive 为我方给出的物品，itemsToReceive 为向对方索取的物品
+// 需要手机令牌确认且提供了 maFileContent 时自动完成确认
+func (d *Dao) SendTradeOffer(tradeURL string, itemsToGive []Model.TradeOfferItem, itemsToReceive []Model.TradeOfferItem, message string, maFileContent string) (*Model.TradeOfferActionResult, error) {
+	partner, err := ParseTradeURL(tradeURL)
+	if err != nil {
+		return nil, err
+	}
+	if len(itemsToGive) == 0 && len(itemsToReceive) == 0 {
+		return nil, fmt.Errorf("%w: 报价中没有任何物品", Errors.ErrTradeOfferFailed)
+	}
+
+	cookies := d.GetLoginCookies()[Constants.Domain.Community]
+	if cookies == nil {
+		return nil, Errors.Error("steamcommunity.com cookie not found")
+	}
+
+	offer, err := json.Marshal(struct {
+		NewVersion bool             `json:"newversion"`
+		Version    int              `json:"version"`
+		Me         tradeOfferAssets `json:"me"`
+		Them       tradeOfferAssets `json:"them"`
+	}{
+		NewVersion: true,
+		Version:    len(itemsToGive) + len(itemsToReceive) + 1,
+		Me:         newTradeOfferAssets(itemsToGive),
+		Them:       newTradeOfferAssets(itemsToReceive),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	createParams := "{}"
+	if partner.Token != "" {
+		createParams = fmt.Sprintf(`{"trade_offer_access_token":%q}`, partner.Token)
+	}
+
+	data := url.Values{}
+	data.Set("sessionid", cookies.SessionId)
+	data.Set("serverid", "1")
+	data.Set("partner", strconv.FormatUint(partner.SteamID(), 10))
+	data.Set("tradeoffermessage", message)
+	data.Set("json_tradeoffer", string(offer))
+	data.Set("captcha", "")
+	data.Set("trade_offer_create_params", createParams)
+
+	req, err := d.Request(http.MethodPost, Constants.SendTradeOffer, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	referer := Constants.TradeOffer + "new/?partner=" + strconv.FormatUint(uint64(partner.Partner), 10)
+	if partner.Token != "" {
+		referer += "&token=" + url.QueryEscape(partner.Token)
+	}
+	req.Header.Set("Origin", Constants.CommunityOrigin)
+	req.Header.Set("Referer", referer)
+
+	body, err := d.doTradeOfferRequest(req, "发送报价")
+	if err != nil {
+		return nil, err
+	}
+
+	var sendResp Model.SendTradeOfferResponse
+	if err := json.Unmarshal(body, &sendResp); err != nil {
+		return nil, fmt.Errorf("解析发送报价响应失败: %w", err)
+	}
+	if sendResp.StrError != "" || sendResp.TradeOfferID == "" {
+		return nil, fmt.Errorf("%w: %s", Errors.ErrTradeOfferFailed, sendResp.StrError)
+	}
+
+	Logger.Infof("用户 [%s] 向 [%d] 发送报价成功，报价ID: %s", d.GetUsername(), partner.SteamID(), sendResp.TradeOfferID)
+
+	result := &Model.TradeOfferActionResult{
+		TradeOfferID:            sendResp.TradeOfferID,
+		NeedsMobileConfirmation: sendResp.NeedsMobileConfirmation,
+		NeedsEmailConfirmation:  sendResp.NeedsEmailConfirmation,
+	}
+
+	if sendResp.NeedsMobileConfirmation && maFileContent != "" {
+		if err := d.ConfirmTradeOffer(sendResp.TradeOfferID, maFileContent); err != nil {
+			return result, err
+		}
+		result.Confirmed = true
+	}
+
+	return result, nil
+}
+
+// AcceptTradeOffer 接受收到的报价
+// 需要手机令牌确认且提供了 maFileContent 时自动完成确认
+func (d *Dao) AcceptTradeOffer(tradeOfferID string, maFileContent string) (*Model.TradeOfferActionResult, error) {
+	offer, err := d.GetTradeOffer(tradeOfferID)
+	if err != nil {
+		return nil, err
+	}
+	if offer.IsOurOffer {
+		return nil, fmt.Errorf("%w: 不能接受自己发出的报价 %s", Errors.ErrTradeOfferFailed, tradeOfferID)
+	}
+	if offer.State != Model.TradeOfferStateActive {
+		return nil, fmt.Errorf("%w: 报价 %s 当前状态为 %s", Errors.ErrTradeOfferFailed, tradeOfferID, offer.State)
+	}
+
+	cookies := d.GetLoginCookies()[Constants.Domain.Community]
+	if cookies == nil {
+		return nil, Errors.Error("steamcommunity.com cookie not found")
+	}
+
+	data := url.Values{}
+	data.Set("sessionid", cookies.SessionId)
+	data.Set("serverid", "1")
+	data.Set("tradeofferid", tradeOfferID)
+	data.Set("partner", strconv.FormatUint(offer.PartnerSteamID(), 10))
+	data.Set("captcha", "")
+
+	req, err := d.Request(http.MethodPost, Constants.TradeOffer+tradeOfferID+"/accept", strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Origin", Constants.CommunityOrigin)
+	req.Header.Set("Referer", Constants.TradeOffer+tradeOfferID+"/")
+
+	body, err := d.doTradeOfferRequest(req, "接受报价")
+	if err != nil {
+		return nil, err
+	}
+
+	var acceptResp Model.AcceptTradeOfferResponse
+	if err := json.Unmarshal(body, &acceptResp); err != nil {
+		return nil, fmt.Errorf("解析接受报价响应失败: %w", err)
+	}
+	if acceptResp.StrError != "" {
+		return nil, fmt.Errorf("%w: %s", Errors.ErrTradeOfferFailed, acceptResp.StrError)
+	}
+
+	Logger.Infof("用户 [%s] 接受报价 %s 成功", d.GetUsername(), tradeOfferID)
+
+	result := &Model.TradeOfferActionResult{
+		TradeOfferID:            tradeOfferID,
+		TradeID:                 acceptResp.TradeID,
+		NeedsMobileConfirmation: acceptResp.NeedsMobileConfirmation,
+		NeedsEmailConfirmation:  acceptResp.NeedsEmailConfirmation,
+	}
+
+	if acceptResp.NeedsMobileConfirmation && maFileContent != "" {
+		if err := d.ConfirmTradeOffer(tradeOfferID, maFileContent); err != nil {
+			return result, err
+		}
+		result.Confirmed = true
+	}
+
+	return result, nil
+}
+
+// DeclineTradeOffer 拒绝收到的报价
+func (d *Dao) DeclineTradeOffer(tradeOfferID string) error {
+	return d.closeTradeOffer(tradeOfferID, "decline", "拒绝报价")
+}
+
+// CancelTradeOffer 取消我方发出的报价
+func (d *Dao) CancelTradeOffer(tradeOfferID string) error {
+	return d.closeTradeOffer(tradeOfferID, "cancel", "取消报价")
+}
+
+func (d *Dao) closeTradeOffer(tradeOfferID string, action string, name string) error {
+	cookies := d.GetLoginCookies()[Constants.Domain.Community]
+	if cookies == nil {
+		return Errors.Error("steamcommunity.com cookie not found")
+	}
+
+	data := url.Values{}
+	data.Set("sessionid", cookies.SessionId)
+
+	req, err := d.Request(http.MethodPost, Constants.TradeOffer+tradeOfferID+"/"+action, strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Origin", Constants.CommunityOrigin)
+	req.Header.Set("Referer", Constants.TradeOffer+tradeOfferID+"/")
+
+	body, err := d.doTradeOfferRequest(req, name)
+	if err != nil {
+		return err
+	}
+
+	var closeResp struct {
+		TradeOfferID string `json:"tradeofferid"`
+		StrError     string `json:"strError"`
+	}
+	if err := json.Unmarshal(body, &closeResp); err != nil {
+		return fmt.Errorf("解析%s响应失败: %w", name, err)
+	}
+	if closeResp.StrError != "" || closeResp.TradeOfferID != tradeOfferID {
+		return fmt.Errorf("%w: %s", Errors.ErrTradeOfferFailed, closeResp.StrError)
+	}
+
+	Logger.Infof("用户 [%s] %s %s 成功", d.GetUsername(), name, tradeOfferID)
+	return nil
+}
+
+// doTradeOfferRequest 执行报价相关的社区请求，Steam 在失败时会以 500 状态码返回带 strError 的 JSON
+func (d *Dao) doTradeOfferRequest(req *http.Request, name string) ([]byte, error) {
+	resp, err := d.RetryRequest(Constants.Tries, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	Logger.Debugf("[%s] HTTP响应状态码: %d，响应内容: %s", name, resp.StatusCode, string(body))
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%s失败: %w", name, Errors.ErrRateLimited)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			StrError string `json:"strError"`
+		}
+		if json.Unmarshal(body, &errResp) == nil && errResp.StrError != "" {
+			return nil, fmt.Errorf("%w: %s", Errors.ErrTradeOfferFailed, errResp.StrError)
+		}
+		return nil, Errors.ResponseError(resp.StatusCode)
+	}
+
+	return body, nil
+}
+
+// ConfirmTradeOffer 使用令牌文件确认指定报价
+// 报价刚发出时确认项可能尚未生成，找不到时会稍作等待后重试
+func (d *Dao) ConfirmTradeOffer(tradeOfferID string, maFileContent string) error {
+	username := d.GetUsername()
+
+	pt, err := Utils.LoadMaFile(maFileContent)
+	if err != nil {
+		Logger.Errorf("加载 [%s] 令牌文件失败，错误： %v", username, err)
+		return err
+	}
+
+	for i := range Constants.Tries {
+		confirmations, err := d.getConfirmations(pt)
+		if err != nil {
+			return err
+		}
+
+		for _, conf := range confirmations {
+			if conf.Type != tradeOfferConfirmationType || conf.CreatorID != tradeOfferID {
+				continue
+			}
+			if err := d.AllowSingleConfirmation(pt, conf, 0); err != nil {
+				Logger.Errorf("确认报价 %s 失败，用户: [%s], 错误: %v", tradeOfferID, username, err)
+				return err
+			}
+			Logger.Infof("确认报价 %s 成功，用户: [%s]", tradeOfferID, username)
+			return nil
+		}
+
+		Logger.Debugf("第 %d 次未找到报价 %s 的确认项，用户: [%s]", i+1, tradeOfferID, username)
+		time.Sleep(time.Second)
+	}
+
+	return fmt.Errorf("%w: %s", Errors.ErrTradeConfirmationNotFound, tradeOfferID)
+}
+
+// GetTradeOffers 获取报价列表，物品会附带描述信息
+// query 为 nil 时查询所有进行中的发出和收到的报价
+func (d *Dao) GetTradeOffers(query *Model.TradeOffersQuery) (*Model.TradeOffers, error) {
+	if query == nil {
+		query = &Model.TradeOffersQuery{Sent: true, Received: true, ActiveOnly: true}
+	}
+	language := query.Language
+	if language == "" {
+		language = "english"
+	}
+
+	accessToken, err := d.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	offers := &Model.TradeOffers{
+		Sent:     make([]Model.TradeOffer, 0),
+		Received: make([]Model.TradeOffer, 0),
+	}
+
+	cursor := 0
+	for {
+		params := Param.Params{}
+		params.SetString("access_token", accessToken)
+		params.SetString("get_sent_offers", strconv.FormatBool(query.Sent))
+		params.SetString("get_received_offers", strconv.FormatBool(query.Received))
+		params.SetString("get_descriptions", "true")
+		params.SetString("active_only", strconv.FormatBool(query.ActiveOnly))
+		params.SetString("historical_only", strconv.FormatBool(query.HistoricalOnly))
+		params.SetString("language", language)
+		params.SetInt64("cursor", int64(cursor))
+		if !query.HistoricalCutoff.IsZero() {
+			params.SetInt64("time_historical_cutoff", query.HistoricalCutoff.Unix())
+		}
+
+		var response Model.TradeOffersResponse
+		if err := d.getEconServiceJSON(Constants.GetTradeOffers+"?"+params.ToUrl(), "获取报价列表", &response); err != nil {
+			return nil, err
+		}
+
+		descMap := tradeOfferDescriptionMap(response.Response.Descriptions)
+		for _, offer := range response.Response.TradeOffersSent {
+			offers.Sent = append(offers.Sent, attachTradeOfferDescriptions(offer, descMap))
+		}
+		for _, offer := range response.Response.TradeOffersReceived {
+			offers.Received = append(offers.Received, attachTradeOfferDescriptions(offer, descMap))
+		}
+
+		if response.Response.NextCursor == 0 || response.Response.NextCursor == cursor {
+			break
+		}
+		cursor = response.Response.NextCursor
+	}
+
+	Logger.Infof("获取用户 [%s] 的报价完成，发出: %d，收到: %d", d.GetUsername(), len(offers.Sent), len(offers.Received))
+
+	return offers, nil
+}
+
+// GetTradeOffer 获取单个报价详情
+func (d *Dao) GetTradeOffer(tradeOfferID string) (*Model.TradeOffer, error) {
+	accessToken, err := d.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	params := Param.Params{}
+	params.SetString("access_token", accessToken)
+	params.SetString("tradeofferid", tradeOfferID)
+	params.SetString("get_descriptions", "true")
+	params.SetString("language", "english")
+
+	var response Model.TradeOfferResponse
+	if err := d.getEconServiceJSON(Constants.GetTradeOffer+"?"+params.ToUrl(), "获取报价", &response); err != nil {
+		return nil, err
+	}
+	if response.Response.Offer == nil {
+		return nil, fmt.Errorf("%w: %s", Errors.ErrTradeOfferNotFound, tradeOfferID)
+	}
+
+	offer := attachTradeOfferDescriptions(*response.Response.Offer, tradeOfferDescriptionMap(response.Response.Descriptions))
+	return &offer, nil
+}
+
+// GetTradeHoldDurations 查询与对方交易时双方的暂挂时长
+// 对方不是好友时需要提供交易令牌（交易链接中的 token）
+func (d *Dao) GetTradeHoldDurations(partnerSteamID uint64, tradeToken string) (*Model.TradeHoldDurations, error) {
+	accessToken, err := d.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	params := Param.Params{}
+	params.SetString("access_token", accessToken)
+	params.SetString("steamid_target", strconv.FormatUint(partnerSteamID, 10))
+	if tradeToken != "" {
+		params.SetString("trade_offer_access_token", tradeToken)
+	}
+
+	var response Model.TradeHoldDurationsResponse
+	if err := d.getEconServiceJSON(Constants.GetTradeHoldDurations+"?"+params.ToUrl(), "获取交易暂挂时长", &response); err != nil {
+		return nil, err
+	}
+
+	return &Model.TradeHoldDurations{
+		My:    time.Duration(response.Response.MyEscrow.EscrowEndDurationSeconds) * time.Second,
+		Their: time.Duration(response.Response.TheirEscrow.EscrowEndDurationSeconds) * time.Second,
+		Both:  time.Duration(response.Response.BothEscrow.EscrowEndDurationSeconds) * time.Second,
+	}, nil
+}
+
+func (d *Dao) getEconServiceJSON(requestURL string, name string, v any) error {
+	req, err := d.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.RetryRequest(Constants.Tries, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%s失败: %w", name, Errors.ErrRateLimited)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Errors.ResponseError(resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("解析%s响应失败: %w", name, err)
+	}
+	return nil
+}
+
+func tradeOfferDescriptionMap(descriptions []Model.Description) map[string]*Model.Description {
+	descMap := make(map[string]*Model.Description, len(descriptions))
+	for i := range descriptions {
+		desc := &descriptions[i]
+		descMap[strconv.Itoa(desc.AppID)+"_"+desc.ClassID+"_"+desc.InstanceID] = desc
+	}
+	return descMap
+}
+
+func attachTradeOfferDescriptions(offer Model.TradeOffer, descMap map[string]*Model.Description) Model.TradeOffer {
+	for _, assets := range [][]Model.TradeOfferAsset{offer.ItemsToGive, offer.ItemsToReceive} {
+		for i := range assets {
+			if desc, ok := descMap[strconv.Itoa(assets[i].AppID)+"_"+assets[i].ClassID+"_"+assets[i].InstanceID]; ok {
+				assets[i].Description = desc
+			}
+		}
+	}
+	return offer
+}
@@ -0,0 +1,160 @@
+package Dao
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/JovanniChen/SteamDB/Steam/Constants"
+	"github.com/JovanniChen/SteamDB/Steam/Logger"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+	"github.com/JovanniChen/SteamDB/Steam/Param"
+)
+
+// tradeHistoryPageSize 交易历史每页数量
+const tradeHistoryPageSize = 100
+
+// GetTradeHistory 获取一页交易历史，物品会附带描述信息
+// 翻页时将上一页最后一笔交易的时间和ID分别填入 StartAfterTime 和 StartAfterTradeID
+func (d *Dao) GetTradeHistory(query *Model.TradeHistoryQuery) (*Model.TradeHistoryPage, error) {
+	if query == nil {
+		query = &Model.TradeHistoryQuery{}
+	}
+	maxTrades := query.MaxTrades
+	if maxTrades <= 0 {
+		maxTrades = tradeHistoryPageSize
+	}
+	language := query.Language
+	if language == "" {
+		language = "english"
+	}
+
+	accessToken, err := d.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	params := Param.Params{}
+	params.SetString("access_token", accessToken)
+	params.SetInt64("max_trades", int64(maxTrades))
+	params.SetString("get_descriptions", "true")
+	params.SetString("include_failed", strconv.FormatBool(query.IncludeFailed))
+	params.SetString("include_total", "true")
+	params.SetString("navigating_back", strconv.FormatBool(query.NavigatingBack))
+	params.SetString("language", language)
+	if !query.StartAfterTime.IsZero() {
+		params.SetInt64("start_after_time", query.StartAfterTime.Unix())
+	}
+	if query.StartAfterTradeID != "" {
+		params.SetString("start_after_tradeid", query.StartAfterTradeID)
+	}
+
+	var response Model.TradeHistoryResponse
+	if err := d.getEconServiceJSON(Constants.GetTradeHistory+"?"+params.ToUrl(), "获取交易历史", &response); err != nil {
+		return nil, err
+	}
+
+	descMap := tradeOfferDescriptionMap(response.Response.Descriptions)
+	trades := make([]Model.TradeHistoryTrade, 0, len(response.Response.Trades))
+	for _, trade := range response.Response.Trades {
+		for _, assets := range [][]Model.TradeHistoryAsset{trade.AssetsGiven, trade.AssetsReceived} {
+			for i := range assets {
+				if desc, ok := descMap[strconv.Itoa(assets[i].AppID)+"_"+assets[i].ClassID+"_"+assets[i].InstanceID]; ok {
+					assets[i].Description = desc
+				}
+			}
+		}
+		trades = append(trades, trade)
+	}
+
+	return &Model.TradeHistoryPage{
+		TotalTrades: response.Response.TotalTrades,
+		More:        response.Response.More,
+		Trades:      trades,
+	}, nil
+}
+
+// tradeHistorySyncState 交易历史同步游标在 sync_state 中的名称
+// 游标格式为 "<tradeid>:<time_init>"，旧版本只保存了 tradeid
+const tradeHistorySyncState = "trade_history"
+
+// parseTradeHistoryCursor 解析交易历史同步游标，没有时间时 cursorTime 为 0
+func parseTradeHistoryCursor(value string) (tradeID string, cursorTime int64) {
+	tradeID, timeText, ok := strings.Cut(value, ":")
+	if ok {
+		cursorTime, _ = strconv.ParseInt(timeText, 10, 64)
+	}
+	return tradeID, cursorTime
+}
+
+// SyncTradeHistory 增量同步交易历史到数据库，返回本次新增的交易（按时间从新到旧）
+// 从最新的交易开始翻页，直到遇到上一次完整同步时最新的交易（游标）或早于游标时间的交易，并且已越过数据库中最早的未结束交易，
+// 以便更新暂挂中交易的状态；同步完成后才更新游标，中途失败时下一次同步会补齐中间缺失的交易
+func (d *Dao) SyncTradeHistory(includeFailed bool) ([]Model.TradeHistoryTrade, error) {
+	steamID := d.GetSteamID()
+	newTrades := make([]Model.TradeHistoryTrade, 0)
+
+	cursorValue, err := GetSyncState(steamID, tradeHistorySyncState)
+	if err != nil {
+		return newTrades, err
+	}
+	// 游标交易可能不再返回（切换 includeFailed 或交易已过期），因此同时按时间判断
+	cursor, cursorTime := parseTradeHistoryCursor(cursorValue)
+	pendingSince, hasPending, err := GetOldestPendingTradeTime(steamID)
+	if err != nil {
+		return newTrades, err
+	}
+
+	var newest *Model.TradeHistoryTrade
+	reachedCursor := false
+	query := &Model.TradeHistoryQuery{IncludeFailed: includeFailed}
+	for {
+		page, err := d.GetTradeHistory(query)
+		if err != nil {
+			return newTrades, err
+		}
+		if len(page.Trades) == 0 {
+			break
+		}
+
+		for _, trade := range page.Trades {
+			if newest == nil {
+				first := trade
+				newest = &first
+			}
+			if (cursor != "" && trade.TradeID == cursor) || (cursorTime > 0 && trade.TimeInit < cursorTime) {
+				reachedCursor = true
+			}
+
+			// 已保存的交易同样写入，更新其状态
+			inserted, err := SaveTradeHistoryTrade(steamID, &trade)
+			if err != nil {
+				return newTrades, err
+			}
+			if inserted {
+				newTrades = append(newTrades, trade)
+			}
+		}
+
+		last := page.Trades[len(page.Trades)-1]
+		if reachedCursor && (!hasPending || last.TimeInit < pendingSince) {
+			break
+		}
+		if !page.More {
+			break
+		}
+
+		query.StartAfterTime = time.Unix(last.TimeInit, 0)
+		query.StartAfterTradeID = last.TradeID
+	}
+
+	if newest != nil {
+		value := newest.TradeID + ":" + strconv.FormatInt(newest.TimeInit, 10)
+		if err := SaveSyncState(steamID, tradeHistorySyncState, value); err != nil {
+			return newTrades, err
+		}
+	}
+
+	Logger.Infof("同步用户[%s]的交易历史完成，新增: %d", d.GetUsername(), len(newTrades))
+	return newTrades, nil
+}
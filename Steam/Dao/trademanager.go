@@ -0,0 +1,214 @@
+package Dao
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/JovanniChen/SteamDB/Steam/Logger"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+)
+
+const (
+	// defaultTradeOfferPollInterval 默认轮询间隔
+	defaultTradeOfferPollInterval = 30 * time.Second
+	// tradeOfferCutoffMargin 历史报价截止时间向前多取的余量，避免服务器时间误差漏掉变化
+	tradeOfferCutoffMargin = 5 * time.Minute
+	// tradeOfferSyncState 首次轮询完成标记在 sync_state 中的名称
+	tradeOfferSyncState = "trade_offers_initialized"
+)
+
+// TradeOfferHandler 报价事件处理器
+type TradeOfferHandler interface {
+	HandleTradeOfferEvent(event Model.TradeOfferEvent)
+}
+
+// TradeOfferHandlerFunc 使用函数作为报价事件处理器
+type TradeOfferHandlerFunc func(event Model.TradeOfferEvent)
+
+func (f TradeOfferHandlerFunc) HandleTradeOfferEvent(event Model.TradeOfferEvent) {
+	f(event)
+}
+
+// TradeOfferManager 定时轮询报价列表，将状态保存到数据库并在状态变化时触发事件
+type TradeOfferManager struct {
+	dao      *Dao
+	interval time.Duration
+	handler  TradeOfferHandler
+
+	mu      sync.Mutex
+	pollMu  sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+	running bool
+}
+
+// NewTradeOfferManager 创建报价管理器，interval 不大于 0 时使用默认的 30 秒
+func (d *Dao) NewTradeOfferManager(interval time.Duration, handler TradeOfferHandler) *TradeOfferManager {
+	if interval <= 0 {
+		interval = defaultTradeOfferPollInterval
+	}
+	return &TradeOfferManager{
+		dao:      d,
+		interval: interval,
+		handler:  handler,
+	}
+}
+
+// Start 开始后台轮询，重复调用无效果
+func (m *TradeOfferManager) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.running {
+		return
+	}
+	m.running = true
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	go m.loop(m.stop, m.done)
+}
+
+// Stop 停止轮询并等待当前轮询结束
+func (m *TradeOfferManager) Stop() {
+	m.mu.Lock()
+	if !m.running {
+		m.mu.Unlock()
+		return
+	}
+	m.running = false
+	close(m.stop)
+	done := m.done
+	m.mu.Unlock()
+
+	<-done
+}
+
+func (m *TradeOfferManager) loop(stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Poll(); err != nil {
+			Logger.Errorf("轮询报价失败，用户: [%s], 错误: %v", m.dao.GetUsername(), err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll 立即轮询一次报价
+// 首次运行（该账号尚未完成过轮询）时只记录现有报价，并为进行中的收到报价触发 new_received 事件
+func (m *TradeOfferManager) Poll() error {
+	m.pollMu.Lock()
+	defer m.pollMu.Unlock()
+
+	steamID := m.dao.GetSteamID()
+
+	initialized, err := GetSyncState(steamID, tradeOfferSyncState)
+	if err != nil {
+		return err
+	}
+	latest, err := GetLatestTradeOfferUpdate(steamID)
+	if err != nil {
+		return err
+	}
+	// 数据库中已有报价说明之前已经轮询过（兼容没有标记的旧数据）
+	firstPoll := initialized == "" && latest.IsZero()
+
+	query := &Model.TradeOffersQuery{Sent: true, Received: true, ActiveOnly: true}
+	if !latest.IsZero() {
+		query.HistoricalCutoff = latest.Add(-tradeOfferCutoffMargin)
+	}
+
+	offers, err := m.dao.GetTradeOffers(query)
+	if err != nil {
+		return err
+	}
+
+	for _, list := range [][]Model.TradeOffer{offers.Sent, offers.Received} {
+		for i := range list {
+			if err := m.processOffer(steamID, &list[i], firstPoll); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 首次轮询完成后记录标记，之后即使数据库中没有报价也按正常轮询处理
+	if firstPoll {
+		return SaveSyncState(steamID, tradeOfferSyncState, strconv.FormatInt(time.Now().Unix(), 10))
+	}
+
+	return nil
+}
+
+func (m *TradeOfferManager) processOffer(steamID uint64, offer *Model.TradeOffer, firstPoll bool) error {
+	oldState, found, err := GetTradeOfferState(steamID, offer.TradeOfferID)
+	if err != nil {
+		return err
+	}
+
+	if !found || oldState != offer.State {
+		if !firstPoll || (!offer.IsOurOffer && offer.State == Model.TradeOfferStateActive) {
+			for _, eventType := range tradeOfferEventTypes(offer, found) {
+				m.emit(Model.TradeOfferEvent{
+					Type:     eventType,
+					Offer:    *offer,
+					OldState: oldState,
+				})
+			}
+		}
+	}
+
+	return SaveTradeOffer(steamID, offer)
+}
+
+// tradeOfferEventTypes 根据报价的新状态得到需要触发的事件
+func tradeOfferEventTypes(offer *Model.TradeOffer, known bool) []Model.TradeOfferEventType {
+	switch offer.State {
+	case Model.TradeOfferStateActive:
+		if !known && !offer.IsOurOffer {
+			return []Model.TradeOfferEventType{Model.TradeOfferEventNewReceived}
+		}
+		return []Model.TradeOfferEventType{Model.TradeOfferEventStateChanged}
+	case Model.TradeOfferStateAccepted:
+		if len(offer.ItemsToReceive) > 0 {
+			return []Model.TradeOfferEventType{Model.TradeOfferEventAccepted, Model.TradeOfferEventItemsReceived}
+		}
+		return []Model.TradeOfferEventType{Model.TradeOfferEventAccepted}
+	case Model.TradeOfferStateDeclined:
+		return []Model.TradeOfferEventType{Model.TradeOfferEventDeclined}
+	case Model.TradeOfferStateCanceled, Model.TradeOfferStateCanceledBySecondFactor:
+		return []Model.TradeOfferEventType{Model.TradeOfferEventCanceled}
+	case Model.TradeOfferStateExpired:
+		return []Model.TradeOfferEventType{Model.TradeOfferEventExpired}
+	case Model.TradeOfferStateCreatedNeedsConfirmation:
+		return []Model.TradeOfferEventType{Model.TradeOfferEventNeedsConfirmation}
+	case Model.TradeOfferStateInEscrow:
+		return []Model.TradeOfferEventType{Model.TradeOfferEventInEscrow}
+	default:
+		return []Model.TradeOfferEventType{Model.TradeOfferEventStateChanged}
+	}
+}
+
+func (m *TradeOfferManager) emit(event Model.TradeOfferEvent) {
+	Logger.Infof("报价 %s 事件: %s (%s -> %s)", event.Offer.TradeOfferID, event.Type, event.OldState, event.Offer.State)
+	if m.handler == nil {
+		return
+	}
+
+	// 处理器异常不影响后续报价的处理
+	defer func() {
+		if r := recover(); r != nil {
+			Logger.Errorf("处理报价 %s 事件 %s 时发生异常: %v", event.Offer.TradeOfferID, event.Type, r)
+		}
+	}()
+	m.handler.HandleTradeOfferEvent(event)
+}
@@ -13,20 +13,20 @@ import (
 
 	"github.com/JovanniChen/SteamDB/Steam/Constants"
 	"github.com/JovanniChen/SteamDB/Steam/Errors"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
 	"github.com/JovanniChen/SteamDB/Steam/Param"
-	"github.com/JovanniChen/SteamDB/Steam/Utils"
 	"github.com/antchfx/htmlquery"
 )
 
 // UserInfo 用户信息结构体
 // 包含Steam用户的基本信息和账户状态
 type UserInfo struct {
-	Balance     int    // 钱包余额(分)
-	WaitBalance int    // 待处理余额
-	Point       int    // Steam积分
-	PersonName  string // 用户昵称
-	CountryCode string // 国家代码
-	Language    string // 语言设置
+	Balance     Model.Money // 钱包余额
+	WaitBalance Model.Money // 待处理余额
+	Point       int         // Steam积分
+	PersonName  string      // 用户昵称
+	CountryCode string      // 国家代码
+	Language    string      // 语言设置
 }
 
 // userInfo 解析HTML页面获取用户信息
@@ -41,13 +41,14 @@ func (d *Dao) userInfo(body io.ReadCloser) (*UserInfo, error) {
 	}
 
 	info := &UserInfo{}
+	var balanceText, waitBalanceText string
 
 	// 提取钱包余额信息
 	//div[@class="accountData price"]/a/text()
 	for _, name := range htmlquery.Find(doc, `//a[@id="header_wallet_balance"]/text()`) {
 		// fmt.Println("info.Balance.name ->", name.Data)
 		// info.Balance, _ = strconv.Atoi(strings.TrimSpace(name.Data))
-		info.Balance = Utils.WalletConvert(name.Data)
+		balanceText = name.Data
 	}
 
 	// 提取用户昵称
@@ -60,7 +61,7 @@ func (d *Dao) userInfo(body io.ReadCloser) (*UserInfo, error) {
 	for _, name := range htmlquery.Find(doc, `//a[@id="header_wallet_balance"]/span/text()`) {
 		// fmt.Println("info.WaitBalance.name ->", name.Data)
 		// info.WaitBalance, _ = strconv.Atoi(strings.TrimSpace(name.Data))
-		info.WaitBalance = Utils.WalletConvert(name.Data)
+		waitBalanceText = name.Data
 	}
 
 	// 提取应用配置信息(语言、国家等)
@@ -75,6 +76,17 @@ func (d *Dao) userInfo(body io.ReadCloser) (*UserInfo, error) {
 		info.CountryCode = m["COUNTRY"].(string)
 	}
 
+	// 余额文本中的货币符号可能存在歧义（如 "¥"），优先按已获取的钱包货币解析，否则按账号所在地区推测
+	currency, ok := d.fetchedWalletCurrency()
+	if !ok {
+		currency = d.walletCurrency()
+		if info.CountryCode != "" {
+			currency = Model.CurrencyForCountry(info.CountryCode)
+		}
+	}
+	info.Balance = parsePrice(balanceText, currency)
+	info.WaitBalance = parsePrice(waitBalanceText, currency)
+
 	return info, nil
 }
 
@@ -0,0 +1,109 @@
+package Dao
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JovanniChen/SteamDB/Steam/Errors"
+	"github.com/JovanniChen/SteamDB/Steam/Logger"
+	"github.com/JovanniChen/SteamDB/Steam/Model"
+)
+
+const (
+	defaultWalletCheckoutTimeout  = 2 * time.Minute
+	defaultWalletCheckoutInterval = 3 * time.Second
+)
+
+// CheckoutWithWallet 使用 Steam 钱包余额购买账号购物车中的商品
+// 初始化交易后获取最终价格并与钱包余额比较，余额不足时取消交易并返回 *Errors.InsufficientFundsError
+// 付款后轮询交易状态直到成功或失败，只有交易状态明确失败时才取消交易；
+// 扣款请求出错时钱包可能已被扣款，不取消交易而是继续查询状态。超时后交易可能仍在处理，
+// 返回的结果中始终带有交易ID，调用方应使用 GetTransactionStatus 确认结果，不要直接重新购买
+func (d *Dao) CheckoutWithWallet(opts *Model.WalletCheckoutOptions) (*Model.WalletCheckout, error) {
+	options := Model.WalletCheckoutOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = defaultWalletCheckoutTimeout
+	}
+	if options.StatusInterval <= 0 {
+		options.StatusInterval = defaultWalletCheckoutInterval
+	}
+	options.Transaction.PaymentMethod = Model.PaymentMethodSteamWallet
+
+	transID, err := d.InitTransactionWithOptions(&options.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	result := &Model.WalletCheckout{TransID: transID}
+
+	balance, err := d.walletBalance()
+	if err != nil {
+		d.cancelWalletCheckout(transID)
+		return result, err
+	}
+	total, err := d.GetFinalPrice(transID)
+	if err != nil {
+		d.cancelWalletCheckout(transID)
+		return result, err
+	}
+	result.Balance = balance
+	result.Total = Model.NewMoney(int64(total), balance.Currency)
+
+	if err := checkWalletFunds(result.Balance, result.Total); err != nil {
+		d.cancelWalletCheckout(transID)
+		return result, err
+	}
+
+	if err := d.FinalizeTransaction(transID); err != nil {
+		Logger.Warnf("[%s]完成交易 %s 返回错误，按交易状态确认结果: %v", d.GetUsername(), transID, err)
+	}
+
+	deadline := time.Now().Add(options.Timeout)
+	for count := 1; ; count++ {
+		status, err := d.GetTransactionStatus(transID, count)
+		if err != nil {
+			Logger.Warnf("[%s]查询交易 %s 状态失败: %v", d.GetUsername(), transID, err)
+		} else if status.Success == 1 {
+			result.Status = status
+			Logger.Infof("[%s]钱包付款完成，交易: %s，金额: %s", d.GetUsername(), transID, result.Total)
+			return result, nil
+		} else if status.PurchaseResultDetail != 0 {
+			d.cancelWalletCheckout(transID)
+			return result, &Errors.CheckoutError{Code: status.PurchaseResultDetail, Msg: Errors.GetCheckoutError(status.PurchaseResultDetail)}
+		}
+
+		if time.Now().After(deadline) {
+			return result, fmt.Errorf("%w: 交易 %s", Errors.ErrPurchasePaymentTimeout, transID)
+		}
+		time.Sleep(options.StatusInterval)
+	}
+}
+
+// walletBalance 获取钱包余额，与 GetBalance 相同但返回查询错误
+func (d *Dao) walletBalance() (Model.Money, error) {
+	userInfo, err := d.getUserInfo()
+	if err != nil {
+		return Model.Money{}, fmt.Errorf("获取钱包余额失败: %w", err)
+	}
+	if userInfo == nil {
+		return Model.Money{}, errors.New("获取钱包余额失败: 未登录")
+	}
+	return userInfo.Balance, nil
+}
+
+// checkWalletFunds 检查钱包余额是否足够支付
+func checkWalletFunds(balance, required Model.Money) error {
+	if balance.Amount < required.Amount {
+		return &Errors.InsufficientFundsError{Balance: balance.Amount, Required: required.Amount, Currency: int(required.Currency)}
+	}
+	return nil
+}
+
+func (d *Dao) cancelWalletCheckout(transID string) {
+	if err := d.CancelTransaction(transID); err != nil {
+		Logger.Warnf("[%s]取消交易 %s 失败: %v", d.GetUsername(), transID, err)
+	}
+}
@@ -1,10 +1,44 @@
 package Errors
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 )
 
+var (
+	ErrPurchaseNotFound       = errors.New("未找到购买流程")
+	ErrPurchaseCartInvalid    = errors.New("购物车中存在无法购买的商品")
+	ErrPurchaseTotalExceeded  = errors.New("最终价格超出上限")
+	ErrPurchasePaymentTimeout = errors.New("等待付款超时")
+	ErrPurchaseStatusUnknown  = errors.New("无法确认交易状态")
+	ErrPurchaseFailed         = errors.New("购买失败")
+	ErrInsufficientFunds      = errors.New("Steam 钱包余额不足")
+)
+
+// InsufficientFundsError 钱包余额不足以支付交易的最终价格，金额单位为分
+type InsufficientFundsError struct {
+	Balance  int64 // 钱包余额
+	Required int64 // 交易的最终价格
+	Currency int   // Steam 货币ID
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("%s: 余额 %.2f，需要 %.2f（货币 %d）", ErrInsufficientFunds,
+		float64(e.Balance)/100, float64(e.Required)/100, e.Currency)
+}
+
+func (e *InsufficientFundsError) Unwrap() error {
+	return ErrInsufficientFunds
+}
+
+func IsInsufficientFunds(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, ErrInsufficientFunds)
+}
+
 // CheckoutError 自定义结账错误类型，包含错误码与错误消息
 type CheckoutError struct {
 	Code int
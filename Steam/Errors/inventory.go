@@ -0,0 +1,15 @@
+package Errors
+
+import "errors"
+
+var (
+	ErrInventoryPrivate    = errors.New("库存未公开或无权访问")
+	ErrInventoryAppContext = errors.New("未能从库存页面解析到游戏库存信息")
+)
+
+func IsInventoryPrivate(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, ErrInventoryPrivate)
+}
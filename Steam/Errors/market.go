@@ -0,0 +1,25 @@
+package Errors
+
+import "errors"
+
+var (
+	ErrBuyOrderBudgetExceeded = errors.New("订购单总金额超出预算")
+	ErrBuyOrderExists         = errors.New("该物品已有进行中的订购单")
+	ErrBuyOrderNotFound       = errors.New("未找到订购单")
+	ErrBuyOrderCurrency       = errors.New("订购单货币与钱包货币不一致")
+	ErrBuyOrderReplaceFailed  = errors.New("原订购单已取消，但新订购单创建失败")
+)
+
+func IsBuyOrderBudgetExceeded(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, ErrBuyOrderBudgetExceeded)
+}
+
+func IsBuyOrderReplaceFailed(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, ErrBuyOrderReplaceFailed)
+}
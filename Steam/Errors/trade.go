@@ -0,0 +1,17 @@
+package Errors
+
+import "errors"
+
+var (
+	ErrInvalidTradeURL           = errors.New("不可用的交易链接")
+	ErrTradeOfferFailed          = errors.New("交易报价操作失败")
+	ErrTradeOfferNotFound        = errors.New("未找到交易报价")
+	ErrTradeConfirmationNotFound = errors.New("未找到交易报价对应的手机令牌确认")
+)
+
+func IsTradeOfferFailed(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, ErrTradeOfferFailed)
+}
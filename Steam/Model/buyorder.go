@@ -0,0 +1,84 @@
+package Model
+
+import "time"
+
+// BuyOrderStatusResponse market/getbuyorderstatus 接口响应
+type BuyOrderStatusResponse struct {
+	Success           int                `json:"success"`
+	Active            int                `json:"active"`                    // 订购单是否仍在进行中
+	Purchased         int64              `json:"purchased"`                 // 已购买数量
+	Quantity          int64              `json:"quantity,string"`           // 订购总数量
+	QuantityRemaining int64              `json:"quantity_remaining,string"` // 剩余数量
+	Purchases         []BuyOrderPurchase `json:"purchases"`                 // 成交记录
+}
+
+// BuyOrderPurchase 订购单的一次成交
+type BuyOrderPurchase struct {
+	ListingID       string `json:"listingid"`        // 成交的上架ID
+	AppID           int    `json:"appid"`            // 游戏ID
+	ContextID       string `json:"contextid"`        // 库存上下文ID
+	AssetID         string `json:"assetid"`          // 购得物品的资产ID
+	AccountIDSeller uint32 `json:"accountid_seller"` // 卖家 AccountID
+	PriceSubtotal   int64  `json:"price_subtotal"`   // 卖家到手金额
+	PriceFee        int64  `json:"price_fee"`        // 手续费
+	PriceTotal      int64  `json:"price_total"`      // 实际支付金额
+}
+
+// BuyOrderStatus 订购单成交进度
+type BuyOrderStatus struct {
+	BuyOrderID        string             `json:"buy_orderid"`
+	Active            bool               `json:"active"`
+	Purchased         int64              `json:"purchased"`
+	Quantity          int64              `json:"quantity"`
+	QuantityRemaining int64              `json:"quantity_remaining"`
+	Purchases         []BuyOrderPurchase `json:"purchases"`
+}
+
+// ManagedBuyOrderState 受管理订购单的状态
+type ManagedBuyOrderState string
+
+const (
+	ManagedBuyOrderActive    ManagedBuyOrderState = "active"    // 进行中
+	ManagedBuyOrderFilled    ManagedBuyOrderState = "filled"    // 已全部成交
+	ManagedBuyOrderCancelled ManagedBuyOrderState = "cancelled" // 已取消（包括在其他地方取消）
+)
+
+// ManagedBuyOrder 由订购单管理器创建并跟踪的订购单
+type ManagedBuyOrder struct {
+	BuyOrderID        string               `json:"buy_orderid"`
+	AppID             int                  `json:"appid"`
+	MarketHashName    string               `json:"market_hash_name"`
+	Price             Money                `json:"price"`              // 单价
+	Quantity          int64                `json:"quantity"`           // 订购数量
+	QuantityRemaining int64                `json:"quantity_remaining"` // 剩余数量
+	State             ManagedBuyOrderState `json:"state"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
+
+// Committed 订购单剩余部分占用的金额
+func (o ManagedBuyOrder) Committed() Money {
+	if o.State != ManagedBuyOrderActive {
+		return Money{Currency: o.Price.Currency}
+	}
+	return o.Price.Mul(o.QuantityRemaining)
+}
+
+// BuyOrderFill 订购单的成交记录
+type BuyOrderFill struct {
+	BuyOrderID     string    `json:"buy_orderid"`
+	ListingID      string    `json:"listingid"`
+	AppID          int       `json:"appid"`
+	ContextID      string    `json:"contextid"`
+	AssetID        string    `json:"assetid"`
+	MarketHashName string    `json:"market_hash_name"`
+	Price          Money     `json:"price"` // 实际支付金额
+	FilledAt       time.Time `json:"filled_at"`
+}
+
+// BuyOrderManagerOptions 订购单管理器选项
+type BuyOrderManagerOptions struct {
+	Budget        Money         // 所有订购单占用金额的上限（须与钱包货币一致），为零时只受钱包余额限制
+	PollInterval  time.Duration // 后台轮询成交进度的间隔，默认 1 分钟
+	MaFileContent string        // 挂单需要手机令牌确认时使用的令牌文件
+}
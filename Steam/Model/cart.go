@@ -1,10 +1,40 @@
 package Model
 
+import "time"
+
+// AddCartItem 加入购物车的商品
+// 设置了 IsGift、AccountidGiftee 或 GifteeSteamID 时作为礼物购买，否则为自己购买
 type AddCartItem struct {
 	PackageID       uint32
 	BundleID        uint32
-	AccountidGiftee uint32
-	Message         string
+	IsGift          bool      // 是否作为礼物购买
+	IsPrivate       bool      // 是否私密购买（不在个人资料中显示）
+	AccountidGiftee uint32    // 收礼人 AccountID
+	GifteeSteamID   uint64    // 收礼人 SteamID64，AccountidGiftee 为 0 时使用
+	GifteeName      string    // 礼物卡片上的收礼人称呼
+	Message         string    // 礼物留言
+	Sentiment       string    // 礼物祝福语，如 "Best Wishes"
+	Signature       string    // 礼物卡片署名
+	ScheduledSendAt time.Time // 定时发送时间，零值表示立即发送
+}
+
+// Gift 是否作为礼物购买
+func (i AddCartItem) Gift() bool {
+	return i.IsGift || i.AccountidGiftee != 0 || i.GifteeSteamID != 0
+}
+
+// ModifyLineItem 修改购物车商品的选项
+type ModifyLineItem struct {
+	IsGift          bool      // 是否作为礼物购买
+	IsPrivate       bool      // 是否私密购买
+	AccountidGiftee uint32    // 收礼人 AccountID，设置后即视为礼物
+	GifteeSteamID   uint64    // 收礼人 SteamID64，AccountidGiftee 为 0 时使用
+	GifteeName      string    // 收礼人称呼
+	Message         string    // 礼物留言
+	Sentiment       string    // 礼物祝福语
+	Signature       string    // 署名
+	ScheduledSendAt time.Time // 定时发送时间，零值表示立即发送
+	CouponGID       uint64    // 要使用的优惠券，0 表示不使用
 }
 
 type GamePurchaseAction struct {
@@ -13,6 +43,61 @@ type GamePurchaseAction struct {
 	GameName        string `json:"gameName"`        // 游戏名称
 	FinalPrice      string `json:"finalPrice"`      // 最终价格（数字）
 	FinalPriceText  string `json:"finalPriceText"`  // 最终价格文本（带货币符号）
+	Price           Money  `json:"price"`           // 最终价格
 	CountryCode     string `json:"countryCode"`     // 国家代码
 	AddToCartIds    string `json:"addToCartIds"`    // 添加到购物车的ID
 }
+
+// CartProblem 购物车商品无法购买的原因
+// Steam 返回的错误标志含义尚未确认，所有错误都报告为 CartProblemNotPurchasable，原始标志见 CartItemValidation.ErrorFlags
+type CartProblem string
+
+const (
+	CartProblemPriceChanged   CartProblem = "price_changed"   // 价格与加入购物车时不同
+	CartProblemNotPurchasable CartProblem = "not_purchasable" // 无法购买（Steam 返回了错误标志）
+)
+
+// Blocking 是否会导致结算失败，价格变化只需要重新确认价格
+func (p CartProblem) Blocking() bool {
+	return p != CartProblemPriceChanged
+}
+
+// CartItemValidation 购物车中单个商品的校验结果
+type CartItemValidation struct {
+	LineItemID     uint64        `json:"line_item_id"`
+	PackageID      uint32        `json:"packageid,omitempty"`
+	BundleID       uint32        `json:"bundleid,omitempty"`
+	Problems       []CartProblem `json:"problems"`
+	ErrorFlags     []uint32      `json:"error_flags,omitempty"`   // Steam 返回的原始错误标志（比特位或子字段号）
+	WarningFlags   []uint32      `json:"warning_flags,omitempty"` // Steam 返回的原始警告标志，不影响结算
+	Subtotal       Money         `json:"subtotal"`                // 当前价格
+	PriceWhenAdded Money         `json:"price_when_added"`        // 加入购物车时的价格
+}
+
+// Blocking 商品是否存在会导致结算失败的问题
+func (v CartItemValidation) Blocking() bool {
+	for _, problem := range v.Problems {
+		if problem.Blocking() {
+			return true
+		}
+	}
+	return false
+}
+
+// CartValidation 购物车校验结果
+type CartValidation struct {
+	Valid    bool                 `json:"valid"` // 没有会导致结算失败的商品
+	Items    []CartItemValidation `json:"items"`
+	Subtotal Money                `json:"subtotal"` // 预计总价
+}
+
+// BlockingItems 会导致结算失败、需要从购物车删除的商品
+func (v CartValidation) BlockingItems() []CartItemValidation {
+	items := make([]CartItemValidation, 0)
+	for _, item := range v.Items {
+		if item.Blocking() {
+			items = append(items, item)
+		}
+	}
+	return items
+}
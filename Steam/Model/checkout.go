@@ -1,5 +1,34 @@
 package Model
 
+import "time"
+
+// PaymentMethod 结账时使用的支付方式，对应 inittransaction 的 PaymentMethod 参数
+type PaymentMethod string
+
+const (
+	PaymentMethodSteamWallet PaymentMethod = "steamaccount" // Steam 钱包余额
+	PaymentMethodAlipay      PaymentMethod = "alipay"       // 支付宝，需要打开支付链接付款
+	PaymentMethodWeChat      PaymentMethod = "wechat"       // 微信支付，需要打开支付链接付款
+	PaymentMethodCard        PaymentMethod = "visa"         // 账号中已保存的信用卡，其他卡组织可直接使用 PaymentMethod("mastercard") 等
+	PaymentMethodPayPal      PaymentMethod = "paypal"       // PayPal，需要跳转到 PayPal 授权
+)
+
+// TransactionOptions 初始化交易的选项
+type TransactionOptions struct {
+	PaymentMethod            PaymentMethod // 支付方式，默认支付宝
+	BillingCountry           string        // 账单国家/地区代码，默认使用账号所在地区
+	IsGift                   bool          // 是否作为礼物购买
+	GifteeAccountID          uint32        // 收礼人的账号ID（好友代码）
+	GifteeEmail              string        // 收礼人邮箱，不是好友时使用
+	GifteeName               string        // 礼物卡片上的收礼人名称
+	GiftMessage              string        // 礼物留言
+	Sentiment                string        // 礼物卡片上的祝福语
+	Signature                string        // 礼物卡片上的署名
+	ScheduledSendOn          time.Time     // 定时送出礼物的时间，为零时立即送出
+	SaveBillingAddress       bool          // 是否保存账单地址
+	AbortPendingTransactions bool          // 是否取消账号中尚未完成的交易
+}
+
 type TransactionStatusResponse struct {
 	Success              int `json:"success"`
 	PurchaseResultDetail int `json:"purchaseresultdetail"`
@@ -21,3 +50,34 @@ type TransactionStatusResponse struct {
 	StrReceiptPageHTML             string `json:"strReceiptPageHTML"`
 	BShowBRSpecificCreditCardError bool   `json:"bShowBRSpecificCreditCardError"`
 }
+
+// WalletCheckoutOptions 使用 Steam 钱包结账的选项
+type WalletCheckoutOptions struct {
+	Transaction    TransactionOptions // 交易选项，支付方式固定为 Steam 钱包
+	Timeout        time.Duration      // 付款后等待交易完成的最长时间，默认 2 分钟
+	StatusInterval time.Duration      // 查询交易状态的间隔，默认 3 秒
+}
+
+// WalletCheckout 使用 Steam 钱包结账的结果
+type WalletCheckout struct {
+	TransID string                     // 交易ID
+	Total   Money                      // 交易的最终价格
+	Balance Money                      // 付款前的钱包余额
+	Status  *TransactionStatusResponse // 交易完成后的状态和收据
+}
+
+// PaymentHandoff 外部支付的交接信息，用于在其他设备上完成付款
+type PaymentHandoff struct {
+	TransID     string            `json:"transid"`
+	Method      string            `json:"method"`       // 支付表单的提交方式
+	Action      string            `json:"action"`       // 支付表单的提交地址
+	Fields      map[string]string `json:"fields"`       // 支付表单的隐藏字段
+	RedirectURL string            `json:"redirect_url"` // 已解析的支付跳转链接，无法解析时为空，需要提交表单付款
+}
+
+// PaymentHandoffServerOptions 支付交接 HTTP 服务的选项
+type PaymentHandoffServerOptions struct {
+	Addr      string        // 监听地址，默认 "127.0.0.1:0"（仅本机可访问，端口随机）
+	PublicURL string        // 分享给操作员的访问地址前缀，例如 "http://192.168.1.10:8089"，默认根据监听地址生成，监听所有网卡时必须设置，否则 Start 返回错误
+	TokenTTL  time.Duration // 一次性链接的有效期，默认 15 分钟
+}
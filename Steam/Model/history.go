@@ -0,0 +1,49 @@
+package Model
+
+import "time"
+
+// MarketHistoryEventType 市场交易历史事件类型
+type MarketHistoryEventType string
+
+const (
+	MarketHistoryListed    MarketHistoryEventType = "listed"    // 上架物品
+	MarketHistorySold      MarketHistoryEventType = "sold"      // 出售成功
+	MarketHistoryPurchased MarketHistoryEventType = "purchased" // 购买成功
+	MarketHistoryCancelled MarketHistoryEventType = "cancelled" // 取消上架
+	MarketHistoryUnknown   MarketHistoryEventType = "unknown"   // 无法识别的事件
+)
+
+// MarketHistoryResponse market/myhistory/render 接口响应
+type MarketHistoryResponse struct {
+	Success     bool   `json:"success"`
+	PageSize    int    `json:"pagesize"`
+	TotalCount  int    `json:"total_count"`
+	Start       int    `json:"start"`
+	ResultsHTML string `json:"results_html"`
+	Hovers      string `json:"hovers"`
+}
+
+// MarketHistoryEvent 市场交易历史中的一条事件
+type MarketHistoryEvent struct {
+	RowID            string                 `json:"row_id"`            // 历史记录行ID（history_row_xxx_xxx），用于去重
+	ListingID        string                 `json:"listing_id"`        // 关联的上架ID
+	Type             MarketHistoryEventType `json:"type"`              // 事件类型
+	AppID            int                    `json:"appid"`             // 游戏ID
+	ContextID        string                 `json:"contextid"`         // 库存上下文ID
+	AssetID          string                 `json:"assetid"`           // 物品资产ID
+	ItemName         string                 `json:"item_name"`         // 物品名称
+	GameName         string                 `json:"game_name"`         // 游戏名称
+	Price            Money                  `json:"price"`             // 成交/上架价格
+	PriceText        string                 `json:"price_text"`        // 原始价格文本
+	CounterpartyName string                 `json:"counterparty_name"` // 交易对方昵称（仅出售/购买）
+	CounterpartyURL  string                 `json:"counterparty_url"`  // 交易对方个人资料链接
+	ActedOn          time.Time              `json:"acted_on"`          // 事件发生日期
+	ListedOn         time.Time              `json:"listed_on"`         // 物品上架日期
+}
+
+// MarketHistoryPage 一次查询得到的交易历史
+type MarketHistoryPage struct {
+	Start      int                  `json:"start"`       // 起始位置
+	TotalCount int                  `json:"total_count"` // 历史记录总数
+	Events     []MarketHistoryEvent `json:"events"`      // 事件列表（按时间从新到旧）
+}
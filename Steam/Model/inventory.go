@@ -0,0 +1,164 @@
+package Model
+
+import (
+	"strings"
+	"time"
+)
+
+// InventoryItem 库存中的一个资产及其完整描述
+type InventoryItem struct {
+	AppID       int         `json:"appid"`       // 游戏ID
+	ContextID   string      `json:"contextid"`   // 库存上下文ID
+	AssetID     string      `json:"assetid"`     // 资产ID
+	ClassID     string      `json:"classid"`     // 类别ID
+	InstanceID  string      `json:"instanceid"`  // 实例ID
+	Amount      int64       `json:"amount"`      // 数量（可堆叠物品大于1）
+	Description Description `json:"description"` // 物品描述
+}
+
+// IsTradable 是否可交易
+func (i InventoryItem) IsTradable() bool {
+	return i.Description.Tradable == 1
+}
+
+// IsMarketable 是否可在市场出售
+func (i InventoryItem) IsMarketable() bool {
+	return i.Description.Marketable == 1
+}
+
+// IsCommodity 是否为商品
+func (i InventoryItem) IsCommodity() bool {
+	return i.Description.Commodity == 1
+}
+
+// TradableAfter 物品处于交易冷却时返回冷却解除时间，可交易或无法确定时返回 false
+// 优先使用 cache_expiration，其次解析 owner_descriptions 中的 "Tradable After ..." 文本
+func (i InventoryItem) TradableAfter() (time.Time, bool) {
+	if i.IsTradable() {
+		return time.Time{}, false
+	}
+	if i.Description.CacheExpiration != "" {
+		if t, err := time.Parse(time.RFC3339, i.Description.CacheExpiration); err == nil {
+			return t, true
+		}
+	}
+	for _, line := range i.Description.OwnerDescriptions {
+		idx := strings.Index(line.Value, "After ")
+		if idx < 0 {
+			continue
+		}
+		if t, err := time.Parse("Jan 2, 2006 (15:04:05) MST", strings.TrimSpace(line.Value[idx+len("After "):])); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Tag 按分类获取标签，不存在时返回 nil
+func (i InventoryItem) Tag(category string) *DescriptionTag {
+	for idx := range i.Description.Tags {
+		if i.Description.Tags[idx].Category == category {
+			return &i.Description.Tags[idx]
+		}
+	}
+	return nil
+}
+
+// ToItem 转换为简化的 Item 结构
+func (i InventoryItem) ToItem() Item {
+	return Item{
+		AssetID:    i.AssetID,
+		ClassID:    i.ClassID,
+		InstanceID: i.InstanceID,
+		Name:       i.Description.Name,
+		MarketName: i.Description.MarketName,
+		Tradable:   i.IsTradable(),
+		Marketable: i.IsMarketable(),
+	}
+}
+
+// InventoryFilter 库存过滤条件，返回 true 表示保留该物品
+type InventoryFilter func(item InventoryItem) bool
+
+// InventoryTradable 只保留可交易物品
+func InventoryTradable(item InventoryItem) bool {
+	return item.IsTradable()
+}
+
+// InventoryMarketable 只保留可在市场出售的物品
+func InventoryMarketable(item InventoryItem) bool {
+	return item.IsMarketable()
+}
+
+// InventoryNonCommodity 只保留非商品物品
+func InventoryNonCommodity(item InventoryItem) bool {
+	return !item.IsCommodity()
+}
+
+// MatchInventoryFilters 判断物品是否满足所有过滤条件
+func MatchInventoryFilters(item InventoryItem, filters ...InventoryFilter) bool {
+	for _, filter := range filters {
+		if filter != nil && !filter(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// InventoryApp 账号在某个游戏下的库存信息（来自库存页面的 g_rgAppContextData）
+type InventoryApp struct {
+	AppID            int                `json:"appid"`             // 游戏ID
+	Name             string             `json:"name"`              // 游戏名称
+	Icon             string             `json:"icon"`              // 游戏图标
+	Link             string             `json:"link"`              // 商店链接
+	AssetCount       int                `json:"asset_count"`       // 物品总数
+	TradePermissions string             `json:"trade_permissions"` // 交易权限（FULL 等）
+	LoadFailed       int                `json:"load_failed"`       // 是否加载失败
+	OwnerOnly        bool               `json:"owner_only"`        // 是否仅拥有者可见
+	Contexts         []InventoryContext `json:"contexts"`          // 库存上下文列表
+}
+
+// InventoryContext 库存上下文
+type InventoryContext struct {
+	ID         string `json:"id"`          // 上下文ID（contextid）
+	Name       string `json:"name"`        // 上下文名称
+	AssetCount int    `json:"asset_count"` // 物品数量
+}
+
+// InventorySnapshot 某一时刻账号在指定游戏/上下文下的库存快照
+type InventorySnapshot struct {
+	SteamID    uint64          `json:"steamid"`     // 账号 SteamID
+	AppID      int             `json:"appid"`       // 游戏ID
+	ContextID  int             `json:"contextid"`   // 上下文ID
+	TotalCount int             `json:"total_count"` // 库存物品总数
+	Hash       string          `json:"hash"`        // 内容摘要，用于快速判断库存是否变化
+	FetchedAt  time.Time       `json:"fetched_at"`  // 获取时间
+	Items      []InventoryItem `json:"items"`       // 物品列表
+}
+
+// InventoryItemChange 两次快照之间发生变化的物品
+type InventoryItemChange struct {
+	Before InventoryItem `json:"before"` // 上次快照中的物品
+	After  InventoryItem `json:"after"`  // 本次快照中的物品
+	Fields []string      `json:"fields"` // 变化的字段（amount、tradable、marketable、classid 等）
+}
+
+// InventoryDiff 两次库存快照的差异
+type InventoryDiff struct {
+	Skipped   bool                  `json:"skipped"`    // 是否因库存未变化而跳过了完整刷新
+	FirstSync bool                  `json:"first_sync"` // 是否为首次同步（此时所有物品都计入 Added）
+	Added     []InventoryItem       `json:"added"`      // 新增的物品
+	Removed   []InventoryItem       `json:"removed"`    // 移除的物品
+	Changed   []InventoryItemChange `json:"changed"`    // 状态发生变化的物品（如变为可交易）
+}
+
+// HasChanges 是否存在差异
+func (d InventoryDiff) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0
+}
+
+// InventoryRefreshOptions 库存刷新选项
+type InventoryRefreshOptions struct {
+	MinInterval time.Duration // 距上次快照不足该时长时直接跳过，0 表示不限制
+	Force       bool          // 强制完整刷新，不做任何跳过判断
+}
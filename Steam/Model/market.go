@@ -1,10 +1,19 @@
 package Model
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // 库存相关结构体
 type InventoryResponse struct {
-	Success      int8          `json:"success"`
-	Assets       []Asset       `json:"assets"`
-	Descriptions []Description `json:"descriptions"`
+	Success             int8          `json:"success"`
+	Assets              []Asset       `json:"assets"`
+	Descriptions        []Description `json:"descriptions"`
+	MoreItems           int           `json:"more_items"`            // 是否还有下一页
+	LastAssetID         string        `json:"last_assetid"`          // 本页最后一个资产ID，作为下一页的 start_assetid
+	TotalInventoryCount int           `json:"total_inventory_count"` // 库存物品总数
+	Error               string        `json:"error"`                 // 失败时的错误信息
 }
 
 type Asset struct {
@@ -17,27 +26,67 @@ type Asset struct {
 }
 
 type Description struct {
-	ClassID    string `json:"classid"`
-	InstanceID string `json:"instanceid"`
-	Name       string `json:"name"`
-	MarketName string `json:"market_name"`
-	Tradable   int    `json:"tradable"`
-	Marketable int    `json:"marketable"`
-	Commodity  int    `json:"commodity"`
+	AppID                       int                 `json:"appid"`
+	ClassID                     string              `json:"classid"`
+	InstanceID                  string              `json:"instanceid"`
+	Currency                    int                 `json:"currency"`
+	Name                        string              `json:"name"`
+	MarketName                  string              `json:"market_name"`
+	MarketHashName              string              `json:"market_hash_name"`
+	Type                        string              `json:"type"`                          // 物品类型文本
+	NameColor                   string              `json:"name_color"`                    // 名称颜色
+	BackgroundColor             string              `json:"background_color"`              // 背景颜色
+	IconURL                     string              `json:"icon_url"`                      // 图标路径（需拼接 Constants.EconomyImage）
+	IconURLLarge                string              `json:"icon_url_large"`                // 大图标路径
+	Tradable                    int                 `json:"tradable"`                      // 是否可交易
+	Marketable                  int                 `json:"marketable"`                    // 是否可在市场出售
+	Commodity                   int                 `json:"commodity"`                     // 是否为商品（市场按订单簿交易）
+	MarketTradableRestriction   int                 `json:"market_tradable_restriction"`   // 市场购买后的交易限制天数
+	MarketMarketableRestriction int                 `json:"market_marketable_restriction"` // 获得后的上架限制天数
+	Descriptions                []DescriptionLine   `json:"descriptions"`                  // 物品描述
+	OwnerDescriptions           []DescriptionLine   `json:"owner_descriptions"`            // 仅拥有者可见的描述（如交易冷却时间）
+	Actions                     []DescriptionAction `json:"actions"`                       // 物品操作（如检视）
+	OwnerActions                []DescriptionAction `json:"owner_actions"`                 // 仅拥有者可用的操作
+	MarketActions               []DescriptionAction `json:"market_actions"`                // 市场中的操作
+	Tags                        []DescriptionTag    `json:"tags"`                          // 物品标签（品质、类型、稀有度等）
+	FraudWarnings               []string            `json:"fraudwarnings"`                 // 欺诈警告（如物品被改名）
+	CacheExpiration             string              `json:"cache_expiration"`              // 描述缓存过期时间（RFC3339），处于交易冷却的物品为冷却解除时间
+}
+
+// DescriptionLine 物品描述中的一行
+type DescriptionLine struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	Color string `json:"color"`
+	Name  string `json:"name"`
+}
+
+// DescriptionAction 物品操作链接
+type DescriptionAction struct {
+	Link string `json:"link"`
+	Name string `json:"name"`
+}
+
+// DescriptionTag 物品标签
+type DescriptionTag struct {
+	Category              string `json:"category"`
+	InternalName          string `json:"internal_name"`
+	LocalizedCategoryName string `json:"localized_category_name"`
+	LocalizedTagName      string `json:"localized_tag_name"`
+	Color                 string `json:"color"`
 }
 
 // 将 Asset 和 Description 结合起来的结构体
 type Item struct {
-	AssetID    string  `json:"asset_id"`    // 物品资产ID
-	ClassID    string  `json:"class_id"`    // 物品类别ID
-	InstanceID string  `json:"instance_id"` // 物品实例ID
-	Name       string  `json:"name"`        // 物品名称
-	MarketName string  `json:"market_name"` // 市场名称
-	Price      float64 `json:"price"`       // 价格
-	Currency   int     `json:"currency"`    // 货币类型
-	Tradable   bool    `json:"tradable"`    // 是否可交易
-	Marketable bool    `json:"marketable"`  // 是否可在市场交易
-	ListingID  string  `json:"listing_id"`  // 上架ID(如果已上架)
+	AssetID    string `json:"asset_id"`    // 物品资产ID
+	ClassID    string `json:"class_id"`    // 物品类别ID
+	InstanceID string `json:"instance_id"` // 物品实例ID
+	Name       string `json:"name"`        // 物品名称
+	MarketName string `json:"market_name"` // 市场名称
+	Price      Money  `json:"price"`       // 价格
+	Tradable   bool   `json:"tradable"`    // 是否可交易
+	Marketable bool   `json:"marketable"`  // 是否可在市场交易
+	ListingID  string `json:"listing_id"`  // 上架ID(如果已上架)
 }
 
 // PutListResponse 上架物品响应
@@ -104,6 +153,40 @@ type WalletInfo struct {
 	Rwgrsn                           int     `json:"rwgrsn"`
 }
 
+// Balance 钱包余额
+func (w WalletInfo) Balance() Money {
+	return NewMoney(w.WalletBalance, Currency(w.WalletCurrency))
+}
+
+// DelayedBalance 待处理余额
+func (w WalletInfo) DelayedBalance() Money {
+	return NewMoney(w.WalletDelayedBalance, Currency(w.WalletCurrency))
+}
+
+// DefaultWalletInfo 未获取到钱包信息时用于计算手续费的默认值（人民币钱包）
+var DefaultWalletInfo = WalletInfo{
+	WalletCurrency:                   23,
+	WalletCountry:                    "CN",
+	WalletFee:                        1,
+	WalletFeeMinimum:                 1,
+	WalletFeePercent:                 0.05,
+	WalletPublisherFeePercentDefault: 0.10,
+	WalletFeeBase:                    0,
+}
+
+// MarketFee 市场手续费计算结果
+type MarketFee struct {
+	SteamFee     Money `json:"steam_fee"`     // Steam 手续费
+	PublisherFee Money `json:"publisher_fee"` // 发行商手续费
+	Fees         Money `json:"fees"`          // 手续费合计
+	Amount       Money `json:"amount"`        // 买家支付总额
+}
+
+// SellerReceive 卖家实际到手金额
+func (f MarketFee) SellerReceive() Money {
+	return f.Amount.Sub(f.Fees)
+}
+
 type BuyListingFailedResponse struct {
 	Message string `json:"message"`
 }
@@ -112,23 +195,73 @@ type CreateOrderResponse struct {
 	NeedConfirmation bool              `json:"need_confirmation"`
 	Confirmation     map[string]string `json:"confirmation"`
 	Success          int               `json:"success"`
+	BuyOrderID       string            `json:"buy_orderid"` // 创建成功时返回的订购单ID
+	Message          string            `json:"message"`
 }
 
 type GetMyListingResponse struct {
-	Success bool `json:"success"`
-	// PageSize          int                  `json:"pagesize"`
-	// TotalCount        int                  `json:"total_count"`
-	// Assets            map[string]AppAssets `json:"assets"`
-	// Start             int                  `json:"start"`
-	// NumActiveListings int                  `json:"num_active_listings"`
+	Success           bool `json:"success"`
+	PageSize          int  `json:"pagesize"`
+	TotalCount        int  `json:"total_count"`
+	Start             int  `json:"start"`
+	NumActiveListings int  `json:"num_active_listings"`
+	// Assets 按 appid -> contextid -> assetid 索引的上架物品资产，没有上架物品时 Steam 返回空数组
+	Assets json.RawMessage `json:"assets"`
 	// Hovers            string               `json:"hovers"`
 	ResultsHTML string `json:"results_html"`
 }
 
+// ListingAsset 上架列表中的物品资产信息
+type ListingAsset struct {
+	AppID      int    `json:"appid"`
+	ContextID  string `json:"contextid"`
+	AssetID    string `json:"id"`
+	ClassID    string `json:"classid"`
+	InstanceID string `json:"instanceid"`
+	// 上架后物品由市场持有并获得新的资产ID，上架前库存中的资产ID和上下文ID保存在 unowned_* 中
+	UnownedID        string `json:"unowned_id"`
+	UnownedContextID string `json:"unowned_contextid"`
+}
+
 type MyListingReponse struct {
-	ListingID          string  // Listing唯一ID
-	AssetID            string  // 物品资产ID
-	MarketHashName     string  // 物品市场名称
-	BuyerPrice         float64 // 买家支付价
-	SellerReceivePrice float64 // 卖家到账价
+	ListingID          string // Listing唯一ID
+	AssetID            string // 物品资产ID
+	AppID              int    // 游戏ID
+	ContextID          string // 库存上下文ID（无法从资产信息中获取时为空）
+	ClassID            string // 类别ID（无法从资产信息中获取时为空）
+	InstanceID         string // 实例ID（无法从资产信息中获取时为空）
+	OriginalAssetID    string // 上架前库存中的资产ID（无法从资产信息中获取时为空）
+	OriginalContextID  string // 上架前库存中的上下文ID（无法从资产信息中获取时为空）
+	MarketHashName     string // 物品市场名称
+	BuyerPrice         Money  // 买家支付价
+	SellerReceivePrice Money  // 卖家到账价
+}
+
+// BuyOrder 进行中的订购单
+type BuyOrder struct {
+	BuyOrderID     string // 订购单ID
+	AppID          int    // 游戏ID
+	MarketHashName string // 物品市场名称
+	Quantity       int    // 剩余求购数量
+	Price          Money  // 单价
+}
+
+// MyListings 用户上架列表汇总
+type MyListings struct {
+	Active    []MyListingReponse // 已上架物品
+	Pending   []MyListingReponse // 等待确认的物品
+	BuyOrders []BuyOrder         // 进行中的订购单
+}
+
+// RemoveListingsOptions 批量下架选项
+type RemoveListingsOptions struct {
+	AppID          int           // 仅下架指定游戏的物品，0表示不限
+	MarketHashName string        // 仅下架指定市场名称的物品，空表示不限
+	Interval       time.Duration // 两次下架请求之间的间隔，0表示使用默认值
+	// OnProgress 每处理完一个物品回调一次，err 为该物品的下架结果
+	OnProgress func(done int, total int, listing MyListingReponse, err error)
+}
+
+type CancelBuyOrderResponse struct {
+	Success int `json:"success"`
 }
@@ -0,0 +1,431 @@
+package Model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Currency Steam 货币ID（ECurrencyCode），与接口中的 currency / wallet_currency 字段一致
+type Currency int
+
+const (
+	CurrencyUnknown Currency = 0
+	CurrencyUSD     Currency = 1
+	CurrencyGBP     Currency = 2
+	CurrencyEUR     Currency = 3
+	CurrencyCHF     Currency = 4
+	CurrencyRUB     Currency = 5
+	CurrencyPLN     Currency = 6
+	CurrencyBRL     Currency = 7
+	CurrencyJPY     Currency = 8
+	CurrencyNOK     Currency = 9
+	CurrencyIDR     Currency = 10
+	CurrencyMYR     Currency = 11
+	CurrencyPHP     Currency = 12
+	CurrencySGD     Currency = 13
+	CurrencyTHB     Currency = 14
+	CurrencyVND     Currency = 15
+	CurrencyKRW     Currency = 16
+	CurrencyTRY     Currency = 17
+	CurrencyUAH     Currency = 18
+	CurrencyMXN     Currency = 19
+	CurrencyCAD     Currency = 20
+	CurrencyAUD     Currency = 21
+	CurrencyNZD     Currency = 22
+	CurrencyCNY     Currency = 23
+	CurrencyINR     Currency = 24
+	CurrencyCLP     Currency = 25
+	CurrencyPEN     Currency = 26
+	CurrencyCOP     Currency = 27
+	CurrencyZAR     Currency = 28
+	CurrencyHKD     Currency = 29
+	CurrencyTWD     Currency = 30
+	CurrencySAR     Currency = 31
+	CurrencyAED     Currency = 32
+	CurrencySEK     Currency = 33
+	CurrencyARS     Currency = 34
+	CurrencyILS     Currency = 35
+	CurrencyBYN     Currency = 36
+	CurrencyKZT     Currency = 37
+	CurrencyKWD     Currency = 38
+	CurrencyQAR     Currency = 39
+	CurrencyCRC     Currency = 40
+	CurrencyUYU     Currency = 41
+)
+
+// CurrencyInfo 货币信息
+type CurrencyInfo struct {
+	ID       Currency // Steam 货币ID
+	Code     string   // ISO 4217 代码
+	Symbol   string   // 显示用货币符号
+	Decimals int      // 显示时的小数位数
+	Markers  []string // 解析价格字符串时可识别的符号/代码
+}
+
+// currencies 已知货币列表
+// 注意：Steam 所有接口中的金额均以 1/100 为单位（包括日元、韩元等不显示小数的货币），Decimals 只影响显示
+var currencies = map[Currency]CurrencyInfo{
+	CurrencyUSD: {CurrencyUSD, "USD", "$", 2, []string{"USD", "$"}},
+	CurrencyGBP: {CurrencyGBP, "GBP", "£", 2, []string{"GBP", "£"}},
+	CurrencyEUR: {CurrencyEUR, "EUR", "€", 2, []string{"EUR", "€"}},
+	CurrencyCHF: {CurrencyCHF, "CHF", "CHF", 2, []string{"CHF"}},
+	CurrencyRUB: {CurrencyRUB, "RUB", "₽", 2, []string{"RUB", "pуб.", "руб.", "pуб", "руб", "₽"}},
+	CurrencyPLN: {CurrencyPLN, "PLN", "zł", 2, []string{"PLN", "zł"}},
+	CurrencyBRL: {CurrencyBRL, "BRL", "R$", 2, []string{"BRL", "R$"}},
+	CurrencyJPY: {CurrencyJPY, "JPY", "¥", 0, []string{"JPY", "¥", "円"}},
+	CurrencyNOK: {CurrencyNOK, "NOK", "kr", 2, []string{"NOK", "kr"}},
+	CurrencyIDR: {CurrencyIDR, "IDR", "Rp", 0, []string{"IDR", "Rp"}},
+	CurrencyMYR: {CurrencyMYR, "MYR", "RM", 2, []string{"MYR", "RM"}},
+	CurrencyPHP: {CurrencyPHP, "PHP", "₱", 2, []string{"PHP", "₱", "P"}},
+	CurrencySGD: {CurrencySGD, "SGD", "S$", 2, []string{"SGD", "S$"}},
+	CurrencyTHB: {CurrencyTHB, "THB", "฿", 2, []string{"THB", "฿"}},
+	CurrencyVND: {CurrencyVND, "VND", "₫", 0, []string{"VND", "₫"}},
+	CurrencyKRW: {CurrencyKRW, "KRW", "₩", 0, []string{"KRW", "₩"}},
+	CurrencyTRY: {CurrencyTRY, "TRY", "₺", 2, []string{"TRY", "TL", "₺"}},
+	CurrencyUAH: {CurrencyUAH, "UAH", "₴", 2, []string{"UAH", "₴"}},
+	CurrencyMXN: {CurrencyMXN, "MXN", "Mex$", 2, []string{"MXN", "Mex$"}},
+	CurrencyCAD: {CurrencyCAD, "CAD", "CDN$", 2, []string{"CAD", "CDN$", "C$"}},
+	CurrencyAUD: {CurrencyAUD, "AUD", "A$", 2, []string{"AUD", "A$"}},
+	CurrencyNZD: {CurrencyNZD, "NZD", "NZ$", 2, []string{"NZD", "NZ$"}},
+	CurrencyCNY: {CurrencyCNY, "CNY", "¥", 2, []string{"CNY", "RMB", "¥", "￥", "元"}},
+	CurrencyINR: {CurrencyINR, "INR", "₹", 0, []string{"INR", "₹"}},
+	CurrencyCLP: {CurrencyCLP, "CLP", "CLP$", 0, []string{"CLP", "CLP$"}},
+	CurrencyPEN: {CurrencyPEN, "PEN", "S/.", 2, []string{"PEN", "S/."}},
+	CurrencyCOP: {CurrencyCOP, "COP", "COL$", 0, []string{"COP", "COL$"}},
+	CurrencyZAR: {CurrencyZAR, "ZAR", "R", 2, []string{"ZAR", "R"}},
+	CurrencyHKD: {CurrencyHKD, "HKD", "HK$", 2, []string{"HKD", "HK$"}},
+	CurrencyTWD: {CurrencyTWD, "TWD", "NT$", 0, []string{"TWD", "NT$"}},
+	CurrencySAR: {CurrencySAR, "SAR", "SR", 2, []string{"SAR", "SR"}},
+	CurrencyAED: {CurrencyAED, "AED", "AED", 2, []string{"AED"}},
+	CurrencySEK: {CurrencySEK, "SEK", "kr", 2, []string{"SEK", "kr"}},
+	CurrencyARS: {CurrencyARS, "ARS", "ARS$", 2, []string{"ARS", "ARS$"}},
+	CurrencyILS: {CurrencyILS, "ILS", "₪", 2, []string{"ILS", "₪"}},
+	CurrencyBYN: {CurrencyBYN, "BYN", "Br", 2, []string{"BYN", "Br"}},
+	CurrencyKZT: {CurrencyKZT, "KZT", "₸", 0, []string{"KZT", "₸"}},
+	CurrencyKWD: {CurrencyKWD, "KWD", "KD", 2, []string{"KWD", "KD"}},
+	CurrencyQAR: {CurrencyQAR, "QAR", "QR", 2, []string{"QAR", "QR"}},
+	CurrencyCRC: {CurrencyCRC, "CRC", "₡", 0, []string{"CRC", "₡"}},
+	CurrencyUYU: {CurrencyUYU, "UYU", "$U", 0, []string{"UYU", "$U"}},
+}
+
+// countryCurrencies 国家/地区代码对应的商店货币
+// 欧元区以及 Steam 不支持当地货币的欧洲国家（丹麦、瑞典、捷克、匈牙利、罗马尼亚、保加利亚等）使用欧元定价
+var countryCurrencies = map[string]Currency{
+	"US": CurrencyUSD, "GB": CurrencyGBP, "CH": CurrencyCHF, "LI": CurrencyCHF, "RU": CurrencyRUB,
+	"PL": CurrencyPLN, "BR": CurrencyBRL, "JP": CurrencyJPY, "NO": CurrencyNOK, "ID": CurrencyIDR,
+	"MY": CurrencyMYR, "PH": CurrencyPHP, "SG": CurrencySGD, "TH": CurrencyTHB, "VN": CurrencyVND,
+	"KR": CurrencyKRW, "UA": CurrencyUAH, "MX": CurrencyMXN, "CA": CurrencyCAD, "AU": CurrencyAUD,
+	"NZ": CurrencyNZD, "CN": CurrencyCNY, "IN": CurrencyINR, "CL": CurrencyCLP, "PE": CurrencyPEN,
+	"CO": CurrencyCOP, "ZA": CurrencyZAR, "HK": CurrencyHKD, "TW": CurrencyTWD, "SA": CurrencySAR,
+	"AE": CurrencyAED, "IL": CurrencyILS, "KZ": CurrencyKZT, "KW": CurrencyKWD, "QA": CurrencyQAR,
+	"CR": CurrencyCRC, "UY": CurrencyUYU,
+
+	// 欧元区
+	"AT": CurrencyEUR, "BE": CurrencyEUR, "CY": CurrencyEUR, "DE": CurrencyEUR, "EE": CurrencyEUR,
+	"ES": CurrencyEUR, "FI": CurrencyEUR, "FR": CurrencyEUR, "GR": CurrencyEUR, "HR": CurrencyEUR,
+	"IE": CurrencyEUR, "IT": CurrencyEUR, "LT": CurrencyEUR, "LU": CurrencyEUR, "LV": CurrencyEUR,
+	"MT": CurrencyEUR, "NL": CurrencyEUR, "PT": CurrencyEUR, "SI": CurrencyEUR, "SK": CurrencyEUR,
+	"AD": CurrencyEUR, "MC": CurrencyEUR, "SM": CurrencyEUR, "VA": CurrencyEUR, "ME": CurrencyEUR,
+	"XK": CurrencyEUR, "GF": CurrencyEUR, "GP": CurrencyEUR, "MQ": CurrencyEUR, "RE": CurrencyEUR,
+	"YT": CurrencyEUR, "PM": CurrencyEUR, "BL": CurrencyEUR, "MF": CurrencyEUR, "AX": CurrencyEUR,
+
+	// 其他以欧元定价的欧洲地区
+	"DK": CurrencyEUR, "SE": CurrencyEUR, "CZ": CurrencyEUR, "HU": CurrencyEUR, "RO": CurrencyEUR,
+	"BG": CurrencyEUR, "IS": CurrencyEUR, "FO": CurrencyEUR, "GL": CurrencyEUR, "AL": CurrencyEUR,
+	"BA": CurrencyEUR, "MK": CurrencyEUR, "RS": CurrencyEUR,
+}
+
+// Info 获取货币信息，未知货币返回的 Code 为空
+func (c Currency) Info() CurrencyInfo {
+	if info, ok := currencies[c]; ok {
+		return info
+	}
+	return CurrencyInfo{ID: c, Decimals: 2}
+}
+
+// Code ISO 4217 代码
+func (c Currency) Code() string {
+	return c.Info().Code
+}
+
+// Symbol 货币符号
+func (c Currency) Symbol() string {
+	return c.Info().Symbol
+}
+
+// Decimals 显示时的小数位数
+func (c Currency) Decimals() int {
+	return c.Info().Decimals
+}
+
+// String 实现 fmt.Stringer 接口
+func (c Currency) String() string {
+	if code := c.Code(); code != "" {
+		return code
+	}
+	return fmt.Sprintf("Currency(%d)", int(c))
+}
+
+// CurrencyByCode 根据 ISO 4217 代码查找货币
+func CurrencyByCode(code string) (Currency, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for id, info := range currencies {
+		if info.Code == code {
+			return id, true
+		}
+	}
+	return CurrencyUnknown, false
+}
+
+// CurrencyForCountry 根据国家/地区代码获取商店使用的货币，未知地区返回美元
+func CurrencyForCountry(countryCode string) Currency {
+	if currency, ok := countryCurrencies[strings.ToUpper(countryCode)]; ok {
+		return currency
+	}
+	return CurrencyUSD
+}
+
+// Money 金额，Amount 为 Steam 接口使用的最小单位（1/100），避免浮点数运算误差
+type Money struct {
+	Amount   int64    `json:"amount"`   // 金额（1/100单位，如人民币的“分”）
+	Currency Currency `json:"currency"` // Steam 货币ID
+}
+
+// NewMoney 创建金额
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// IsZero 金额是否为0
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Add 相加，货币不同时以 m 的货币为准
+func (m Money) Add(other Money) Money {
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}
+}
+
+// Sub 相减
+func (m Money) Sub(other Money) Money {
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}
+}
+
+// Mul 乘以数量
+func (m Money) Mul(n int64) Money {
+	return Money{Amount: m.Amount * n, Currency: m.Currency}
+}
+
+// Float64 转换为以主单位表示的浮点数（仅用于展示或兼容旧接口）
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / 100
+}
+
+// Number 按货币的小数位数格式化数字部分，如 "12.34"
+func (m Money) Number() string {
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	if m.Currency.Decimals() == 0 {
+		return sign + strconv.FormatInt(amount/100, 10)
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
+
+// String 格式化为带货币符号的字符串，如 "¥ 12.34"
+func (m Money) String() string {
+	symbol := m.Currency.Symbol()
+	if symbol == "" {
+		return m.Number()
+	}
+	return symbol + " " + m.Number()
+}
+
+// ParseMoney 解析 Steam 页面上各地区格式的价格字符串
+// 支持 "¥ 12.34"、"$0.03 USD"、"0,03€"、"1 234,56 pуб."、"R$ 1.234,56"、"₩ 1,234"、"CHF 1'234.50" 等
+// 字符串中无法识别货币时使用 fallback；符号存在歧义（如 "¥"、"$"、"kr"）且 fallback 可使用该符号时优先 fallback
+func ParseMoney(s string, fallback Currency) (Money, error) {
+	text := strings.TrimSpace(s)
+	if text == "" {
+		return Money{Currency: fallback}, fmt.Errorf("价格为空")
+	}
+
+	currency := detectCurrency(text, fallback)
+
+	amount, err := parseAmount(text)
+	if err != nil {
+		return Money{Currency: currency}, fmt.Errorf("无法解析价格 %q: %w", s, err)
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// MustParseMoney 解析价格，失败时返回 fallback 货币的0值
+func MustParseMoney(s string, fallback Currency) Money {
+	money, _ := ParseMoney(s, fallback)
+	return money
+}
+
+// currencyMarker 价格字符串中的货币标记
+type currencyMarker struct {
+	marker   string
+	currency Currency
+}
+
+// currencyMarkers 按长度从长到短排列的货币标记，保证 "HK$" 先于 "$" 匹配
+var currencyMarkers = func() []currencyMarker {
+	markers := make([]currencyMarker, 0)
+	for id, info := range currencies {
+		for _, marker := range info.Markers {
+			markers = append(markers, currencyMarker{marker: marker, currency: id})
+		}
+	}
+	sort.Slice(markers, func(i, j int) bool {
+		if len(markers[i].marker) != len(markers[j].marker) {
+			return len(markers[i].marker) > len(markers[j].marker)
+		}
+		return markers[i].currency < markers[j].currency
+	})
+	return markers
+}()
+
+// detectCurrency 根据价格字符串中的符号判断货币
+func detectCurrency(text string, fallback Currency) Currency {
+	// 只在非数字部分中查找，避免数字中的字符被误判
+	letters := strings.TrimSpace(amountRegex.ReplaceAllString(text, " "))
+	if letters == "" {
+		return fallback
+	}
+
+	for _, m := range currencyMarkers {
+		if !containsMarker(letters, m.marker) {
+			continue
+		}
+		// 同一符号被多个货币使用时，优先 fallback
+		for _, fallbackMarker := range fallback.Info().Markers {
+			if fallbackMarker == m.marker {
+				return fallback
+			}
+		}
+		return m.currency
+	}
+
+	return fallback
+}
+
+// containsMarker 判断标记是否出现在字符串中；纯字母标记需要独立出现（避免 "Rp" 匹配到 "R"）
+func containsMarker(text string, marker string) bool {
+	idx := strings.Index(text, marker)
+	if idx < 0 {
+		return false
+	}
+	if !isASCIILetters(marker) {
+		return true
+	}
+	for idx >= 0 {
+		before := idx == 0 || !isASCIILetter(text[idx-1])
+		afterPos := idx + len(marker)
+		after := afterPos >= len(text) || !isASCIILetter(text[afterPos])
+		if before && after {
+			return true
+		}
+		next := strings.Index(text[idx+1:], marker)
+		if next < 0 {
+			break
+		}
+		idx += next + 1
+	}
+	return false
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isASCIILetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isASCIILetter(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// amountRegex 匹配价格中的数字部分（包含千位分隔符和小数点）
+var amountRegex = regexp.MustCompile(`\d[\d.,'\s\x{00a0}\x{202f}]*`)
+
+// parseAmount 将价格中的数字部分转换为 1/100 单位的整数
+func parseAmount(text string) (int64, error) {
+	raw := amountRegex.FindString(text)
+	if raw == "" {
+		return 0, fmt.Errorf("未找到数字")
+	}
+
+	// 去掉空格、不换行空格和撇号等千位分隔符
+	digits := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u202f', '\'':
+			return -1
+		}
+		return r
+	}, raw)
+	digits = strings.TrimRight(digits, ".,")
+
+	integerPart, fractionPart := splitDecimal(digits)
+
+	integerPart = strings.NewReplacer(".", "", ",", "").Replace(integerPart)
+	if integerPart == "" {
+		integerPart = "0"
+	}
+	whole, err := strconv.ParseInt(integerPart, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	// 小数部分只保留两位（Steam 的最小单位）
+	if len(fractionPart) > 2 {
+		fractionPart = fractionPart[:2]
+	}
+	for len(fractionPart) < 2 {
+		fractionPart += "0"
+	}
+	fraction, err := strconv.ParseInt(fractionPart, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return whole*100 + fraction, nil
+}
+
+// splitDecimal 判断小数分隔符并拆分整数和小数部分
+// 同时出现 "." 和 "," 时，靠后的为小数点；只出现一种时，后面恰好跟 3 位数字视为千位分隔符
+func splitDecimal(digits string) (string, string) {
+	lastDot := strings.LastIndex(digits, ".")
+	lastComma := strings.LastIndex(digits, ",")
+
+	sep := -1
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		sep = max(lastDot, lastComma)
+	case lastDot >= 0 || lastComma >= 0:
+		pos := max(lastDot, lastComma)
+		sepChar := digits[pos]
+		tail := digits[pos+1:]
+		repeated := strings.Count(digits, string(sepChar)) > 1
+		if !repeated && len(tail) != 3 {
+			sep = pos
+		}
+	}
+
+	if sep < 0 {
+		return digits, ""
+	}
+	return digits[:sep], digits[sep+1:]
+}
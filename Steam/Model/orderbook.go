@@ -0,0 +1,27 @@
+package Model
+
+// ItemOrdersHistogramResponse market/itemordershistogram 接口响应（norender=1）
+type ItemOrdersHistogramResponse struct {
+	Success         int     `json:"success"`
+	HighestBuyOrder string  `json:"highest_buy_order"` // 最高求购价（最小货币单位）
+	LowestSellOrder string  `json:"lowest_sell_order"` // 最低出售价（最小货币单位）
+	BuyOrderGraph   [][]any `json:"buy_order_graph"`   // [价格, 累计数量, 描述]
+	SellOrderGraph  [][]any `json:"sell_order_graph"`  // [价格, 累计数量, 描述]
+}
+
+// OrderBookLevel 订单簿中的一档价格
+type OrderBookLevel struct {
+	Price    Money `json:"price"`    // 买家支付价格
+	Quantity int64 `json:"quantity"` // 该价格的数量
+}
+
+// OrderBook 物品的订单簿
+type OrderBook struct {
+	AppID          int              `json:"appid"`
+	MarketHashName string           `json:"market_hash_name"`
+	ItemNameID     int64            `json:"item_nameid"`
+	LowestSell     Money            `json:"lowest_sell"` // 最低出售价，没有在售时为零
+	HighestBuy     Money            `json:"highest_buy"` // 最高求购价，没有求购时为零
+	SellOrders     []OrderBookLevel `json:"sell_orders"` // 出售订单（价格从低到高）
+	BuyOrders      []OrderBookLevel `json:"buy_orders"`  // 求购订单（价格从高到低）
+}
@@ -0,0 +1,39 @@
+package Model
+
+import "time"
+
+// PriceWatchEventType 价格监控事件类型
+type PriceWatchEventType string
+
+const (
+	PriceWatchEventNewDiscount   PriceWatchEventType = "new_discount"   // 开始打折或折扣变化
+	PriceWatchEventHistoricalLow PriceWatchEventType = "historical_low" // 低于记录中的历史最低价
+	PriceWatchEventFreeToKeep    PriceWatchEventType = "free_to_keep"   // 限时免费领取
+)
+
+// StorePriceRecord 数据库中保存的一次价格记录
+type StorePriceRecord struct {
+	Item        StoreItemID `json:"item"`
+	CountryCode string      `json:"country_code"`
+	Final       Money       `json:"final"`
+	Original    Money       `json:"original"`
+	DiscountPct int32       `json:"discount_pct"`
+	ObservedAt  time.Time   `json:"observed_at"`
+}
+
+// PriceWatchEvent 价格监控事件
+type PriceWatchEvent struct {
+	Type        PriceWatchEventType `json:"type"`
+	Item        StoreItemID         `json:"item"`
+	Name        string              `json:"name"`
+	Price       RegionPrice         `json:"price"`        // 当前价格
+	Previous    *StorePriceRecord   `json:"previous"`     // 上一次记录的价格，首次记录时为空
+	PreviousLow Money               `json:"previous_low"` // 本次之前的历史最低价
+}
+
+// PriceWatcherOptions 价格监控选项
+type PriceWatcherOptions struct {
+	Items     []StoreItemID // 监控的游戏、礼包或捆绑包
+	Countries []string      // 监控的地区代码，为空时使用账号所在地区（账号没有地区时使用钱包地区）
+	Interval  time.Duration // 后台检查间隔，默认 1 小时
+}
@@ -0,0 +1,62 @@
+package Model
+
+import "time"
+
+// PurchaseState 购买流程的状态
+type PurchaseState string
+
+const (
+	PurchaseStateCreated        PurchaseState = "created"         // 已创建，尚未操作购物车
+	PurchaseStateCartReady      PurchaseState = "cart_ready"      // 已清空购物车并加入商品
+	PurchaseStateCartValidated  PurchaseState = "cart_validated"  // 购物车校验通过
+	PurchaseStateTransactionNew PurchaseState = "transaction_new" // 已初始化交易
+	PurchaseStatePriceConfirmed PurchaseState = "price_confirmed" // 已获取并确认最终价格
+	PurchaseStatePaying         PurchaseState = "paying"          // 已提交或即将提交钱包扣款，结果未确认
+	PurchaseStatePaymentPending PurchaseState = "payment_pending" // 等待付款（外部支付链接或钱包扣款）
+	PurchaseStateCompleted      PurchaseState = "completed"       // 购买完成
+	PurchaseStateFailed         PurchaseState = "failed"          // 购买失败，交易已取消
+)
+
+// IsFinal 是否为最终状态
+func (s PurchaseState) IsFinal() bool {
+	return s == PurchaseStateCompleted || s == PurchaseStateFailed
+}
+
+// Purchase 购买流程，保存在数据库中，程序中断后可以继续
+type Purchase struct {
+	ID            int64            `json:"id"`
+	State         PurchaseState    `json:"state"`
+	PaymentMethod PaymentMethod    `json:"payment_method"`
+	Items         []AddCartItem    `json:"items"`
+	TransID       string           `json:"transid"`
+	Total         Money            `json:"total"`       // 最终价格
+	PaymentURL    string           `json:"payment_url"` // 外部支付链接
+	Error         string           `json:"error"`       // 失败原因
+	Receipt       *PurchaseReceipt `json:"receipt"`     // 完成后的收据
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
+
+// PurchaseReceipt 购买完成后的收据
+type PurchaseReceipt struct {
+	PurchaseID      int64         `json:"purchase_id"`
+	TransID         string        `json:"transid"`
+	PaymentMethod   PaymentMethod `json:"payment_method"`
+	Total           Money         `json:"total"`
+	FormattedTotal  string        `json:"formatted_total"`
+	BasePrice       Money         `json:"base_price"`
+	TotalDiscount   Money         `json:"total_discount"`
+	Tax             Money         `json:"tax"`
+	TransactionTime time.Time     `json:"transaction_time"`
+	Items           []AddCartItem `json:"items"`
+}
+
+// PurchaseOptions 购买选项
+type PurchaseOptions struct {
+	PaymentMethod    PaymentMethod           // 支付方式，默认支付宝，目前只支持支付宝和 Steam 钱包
+	DropInvalidItems bool                    // 购物车校验失败时删除无法购买的商品后继续，否则直接失败
+	MaxTotal         Money                   // 最终价格上限（货币须与钱包一致），为零时不限制
+	PaymentTimeout   time.Duration           // 等待付款完成的最长时间，默认 15 分钟，超时后取消交易
+	StatusInterval   time.Duration           // 查询交易状态的间隔，默认 5 秒
+	OnStateChange    func(purchase Purchase) // 状态变化时调用，可在 payment_pending 时取得支付链接
+}
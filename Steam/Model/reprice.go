@@ -0,0 +1,48 @@
+package Model
+
+import "time"
+
+// RepriceAction 改价决策
+type RepriceAction string
+
+const (
+	RepriceKeep   RepriceAction = "keep"   // 保持当前价格
+	RepriceRelist RepriceAction = "relist" // 下架并以新价格重新上架
+	RepriceFailed RepriceAction = "failed" // 改价过程出错
+	// RepriceDelisted 已下架但未能重新上架，物品已回到库存，需要调用方自行处理
+	RepriceDelisted RepriceAction = "delisted"
+)
+
+// RepriceDecision 单个上架物品的改价结果
+type RepriceDecision struct {
+	ListingID      string        `json:"listing_id"`       // 原上架ID
+	AssetID        string        `json:"assetid"`          // 物品资产ID
+	NewAssetID     string        `json:"new_assetid"`      // 下架后回到库存的新资产ID（找到时）
+	AppID          int           `json:"appid"`            // 游戏ID
+	MarketHashName string        `json:"market_hash_name"` // 物品市场名称
+	OldPrice       Money         `json:"old_price"`        // 原买家支付价格
+	NewPrice       Money         `json:"new_price"`        // 新买家支付价格（仅 relist）
+	LowestSell     Money         `json:"lowest_sell"`      // 决策时市场最低出售价
+	Action         RepriceAction `json:"action"`           // 决策
+	Reason         string        `json:"reason"`           // 决策原因或错误信息
+}
+
+// RepricerOptions 自动改价选项
+type RepricerOptions struct {
+	Interval        time.Duration // 后台运行时每轮的间隔，默认 10 分钟
+	RequestInterval time.Duration // 每个物品之间的请求间隔，默认 Constants.ProcessInterval
+	DryRun          bool          // 只生成报告，不下架、不上架
+	MaFileContent   string        // 重新上架时用于手机令牌确认的令牌文件
+	ContextIDs      map[int]int   // 按游戏指定库存上下文ID，默认 Steam(753) 为 6，其余为 2
+}
+
+// RepriceReport 一轮自动改价的报告
+type RepriceReport struct {
+	DryRun    bool              `json:"dry_run"`
+	StartedAt time.Time         `json:"started_at"`
+	Decisions []RepriceDecision `json:"decisions"`
+	Relisted  int               `json:"relisted"` // 已改价（DryRun 时为计划改价）数量
+	Kept      int               `json:"kept"`     // 保持不变数量
+	Failed    int               `json:"failed"`   // 出错数量
+	Delisted  int               `json:"delisted"` // 已下架但未重新上架数量
+}
@@ -0,0 +1,69 @@
+package Model
+
+import (
+	"strconv"
+	"time"
+)
+
+// SellOrder 批量上架中的一个物品
+type SellOrder struct {
+	AppID          int    `json:"appid"`            // 游戏ID
+	ContextID      int    `json:"contextid"`        // 库存上下文ID
+	AssetID        string `json:"assetid"`          // 资产ID
+	Amount         int64  `json:"amount"`           // 上架数量，默认为 1
+	MarketHashName string `json:"market_hash_name"` // 市场名称，供定价回调使用
+	Price          Money  `json:"price"`            // 买家支付的单价，为零时由 SellOptions.Pricer 决定
+}
+
+// SellOrderFromInventory 由库存物品创建上架请求
+func SellOrderFromInventory(item InventoryItem, price Money) SellOrder {
+	contextID, _ := strconv.Atoi(item.ContextID)
+	return SellOrder{
+		AppID:          item.AppID,
+		ContextID:      contextID,
+		AssetID:        item.AssetID,
+		Amount:         1,
+		MarketHashName: item.Description.MarketHashName,
+		Price:          price,
+	}
+}
+
+// SellPricer 定价回调，返回买家支付的单价；返回零金额表示跳过该物品
+type SellPricer func(order SellOrder) (Money, error)
+
+// SellOptions 批量上架选项
+type SellOptions struct {
+	Pricer              SellPricer    // 定价回调，订单未指定价格时调用
+	Interval            time.Duration // 每次上架请求之间的间隔，默认 Constants.ProcessInterval
+	RateLimitBackoff    time.Duration // 遇到 429 后的等待时间（每次重试翻倍），默认 30 秒
+	MaxRateLimitRetries int           // 遇到 429 时的最大重试次数，默认 3
+	ConfirmBatchSize    int           // 每批确认的数量，默认 30
+}
+
+// SellItemStatus 单个物品的上架结果
+type SellItemStatus string
+
+const (
+	SellItemListed            SellItemStatus = "listed"             // 已上架（无需确认或已确认）
+	SellItemNeedsConfirmation SellItemStatus = "needs_confirmation" // 已提交，等待手机令牌/邮箱确认
+	SellItemFailed            SellItemStatus = "failed"             // 上架失败
+	SellItemSkipped           SellItemStatus = "skipped"            // 定价回调未给出价格，已跳过
+)
+
+// SellItemResult 单个物品的上架结果
+type SellItemResult struct {
+	Order         SellOrder      `json:"order"`          // 上架请求
+	Status        SellItemStatus `json:"status"`         // 结果状态
+	Price         Money          `json:"price"`          // 买家支付的单价
+	SellerReceive Money          `json:"seller_receive"` // 卖家到手的单价
+	Reason        string         `json:"reason"`         // 失败原因
+}
+
+// SellReport 批量上架报告
+type SellReport struct {
+	Results           []SellItemResult `json:"results"`
+	Listed            int              `json:"listed"`             // 已上架数量
+	NeedsConfirmation int              `json:"needs_confirmation"` // 等待确认数量
+	Failed            int              `json:"failed"`             // 失败数量
+	Skipped           int              `json:"skipped"`            // 跳过数量
+}
@@ -0,0 +1,82 @@
+package Model
+
+import "github.com/JovanniChen/SteamDB/Steam/Protoc"
+
+// StoreItemType 商店物品类型，对应 StoreItem.item_type
+type StoreItemType uint32
+
+const (
+	StoreItemTypeApp     StoreItemType = 0 // 游戏/应用
+	StoreItemTypePackage StoreItemType = 1 // 礼包（Sub）
+	StoreItemTypeBundle  StoreItemType = 2 // 捆绑包
+)
+
+// StoreItemID 商店物品ID，AppID、PackageID、BundleID 三者只填一个
+type StoreItemID struct {
+	AppID     uint32 `json:"appid,omitempty"`
+	PackageID uint32 `json:"packageid,omitempty"`
+	BundleID  uint32 `json:"bundleid,omitempty"`
+}
+
+// Type 物品类型
+func (id StoreItemID) Type() StoreItemType {
+	switch {
+	case id.PackageID != 0:
+		return StoreItemTypePackage
+	case id.BundleID != 0:
+		return StoreItemTypeBundle
+	default:
+		return StoreItemTypeApp
+	}
+}
+
+// ID 物品类型对应的ID
+func (id StoreItemID) ID() uint32 {
+	switch id.Type() {
+	case StoreItemTypePackage:
+		return id.PackageID
+	case StoreItemTypeBundle:
+		return id.BundleID
+	default:
+		return id.AppID
+	}
+}
+
+// NewStoreItemID 根据类型和ID创建物品ID
+func NewStoreItemID(itemType StoreItemType, id uint32) StoreItemID {
+	switch itemType {
+	case StoreItemTypePackage:
+		return StoreItemID{PackageID: id}
+	case StoreItemTypeBundle:
+		return StoreItemID{BundleID: id}
+	default:
+		return StoreItemID{AppID: id}
+	}
+}
+
+// StoreItems 按类型分组的商店物品
+type StoreItems struct {
+	Apps     []*Protoc.StoreItem `json:"apps"`
+	Packages []*Protoc.StoreItem `json:"packages"`
+	Bundles  []*Protoc.StoreItem `json:"bundles"`
+	Failed   []StoreItemID       `json:"failed"` // 查询失败或在当前地区不可见的物品
+}
+
+// RegionPrice 物品在某个地区的价格
+type RegionPrice struct {
+	CountryCode        string `json:"country_code"`
+	Available          bool   `json:"available"` // 该地区是否可购买
+	Final              Money  `json:"final"`     // 折后价
+	Original           Money  `json:"original"`  // 原价
+	DiscountPct        int32  `json:"discount_pct"`
+	PurchaseOptionName string `json:"purchase_option_name"`
+	PackageID          int32  `json:"packageid,omitempty"` // 价格对应的礼包
+	BundleID           int32  `json:"bundleid,omitempty"`  // 价格对应的捆绑包
+}
+
+// PriceComparison 同一物品在多个地区的价格对比
+type PriceComparison struct {
+	Item    StoreItemID   `json:"item"`
+	Name    string        `json:"name"`
+	Regions []RegionPrice `json:"regions"` // 与请求的地区顺序一致
+}
@@ -0,0 +1,351 @@
+package Model
+
+import "time"
+
+// TradeOfferState 交易报价状态（ETradeOfferState）
+type TradeOfferState int
+
+const (
+	TradeOfferStateInvalid                  TradeOfferState = 1  // 无效
+	TradeOfferStateActive                   TradeOfferState = 2  // 已发出，等待对方处理
+	TradeOfferStateAccepted                 TradeOfferState = 3  // 已接受，物品已交换
+	TradeOfferStateCountered                TradeOfferState = 4  // 对方提出了还价
+	TradeOfferStateExpired                  TradeOfferState = 5  // 已过期
+	TradeOfferStateCanceled                 TradeOfferState = 6  // 发送方已取消
+	TradeOfferStateDeclined                 TradeOfferState = 7  // 接收方已拒绝
+	TradeOfferStateInvalidItems             TradeOfferState = 8  // 报价中的物品已不可用
+	TradeOfferStateCreatedNeedsConfirmation TradeOfferState = 9  // 已创建，等待邮箱或手机令牌确认
+	TradeOfferStateCanceledBySecondFactor   TradeOfferState = 10 // 在二次确认中被取消
+	TradeOfferStateInEscrow                 TradeOfferState = 11 // 交易暂挂中
+)
+
+var tradeOfferStateNames = map[TradeOfferState]string{
+	TradeOfferStateInvalid:                  "Invalid",
+	TradeOfferStateActive:                   "Active",
+	TradeOfferStateAccepted:                 "Accepted",
+	TradeOfferStateCountered:                "Countered",
+	TradeOfferStateExpired:                  "Expired",
+	TradeOfferStateCanceled:                 "Canceled",
+	TradeOfferStateDeclined:                 "Declined",
+	TradeOfferStateInvalidItems:             "InvalidItems",
+	TradeOfferStateCreatedNeedsConfirmation: "CreatedNeedsConfirmation",
+	TradeOfferStateCanceledBySecondFactor:   "CanceledBySecondFactor",
+	TradeOfferStateInEscrow:                 "InEscrow",
+}
+
+func (s TradeOfferState) String() string {
+	if name, ok := tradeOfferStateNames[s]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// IsFinal 报价是否已处于最终状态（不会再发生变化）
+func (s TradeOfferState) IsFinal() bool {
+	switch s {
+	case TradeOfferStateActive, TradeOfferStateCreatedNeedsConfirmation, TradeOfferStateInEscrow:
+		return false
+	}
+	return true
+}
+
+// TradeOfferConfirmationMethod 报价的二次确认方式
+type TradeOfferConfirmationMethod int
+
+const (
+	TradeOfferConfirmationNone      TradeOfferConfirmationMethod = 0 // 无需确认
+	TradeOfferConfirmationEmail     TradeOfferConfirmationMethod = 1 // 邮箱确认
+	TradeOfferConfirmationMobileApp TradeOfferConfirmationMethod = 2 // 手机令牌确认
+)
+
+// TradeURL 解析后的交易链接
+type TradeURL struct {
+	Partner uint32 `json:"partner"` // 对方的 AccountID
+	Token   string `json:"token"`   // 交易令牌，非好友发送报价时必需
+}
+
+// SteamID 对方的 64 位 SteamID
+func (t TradeURL) SteamID() uint64 {
+	return uint64(t.Partner) + 76561197960265728
+}
+
+// TradeOfferItem 发送报价时使用的物品
+type TradeOfferItem struct {
+	AppID     int    `json:"appid"`
+	ContextID string `json:"contextid"`
+	AssetID   string `json:"assetid"`
+	Amount    int64  `json:"amount"`
+}
+
+// ToTradeOfferItem 转换为报价物品
+func (i InventoryItem) ToTradeOfferItem() TradeOfferItem {
+	amount := i.Amount
+	if amount <= 0 {
+		amount = 1
+	}
+	return TradeOfferItem{
+		AppID:     i.AppID,
+		ContextID: i.ContextID,
+		AssetID:   i.AssetID,
+		Amount:    amount,
+	}
+}
+
+// SendTradeOfferResponse tradeoffer/new/send 接口响应
+type SendTradeOfferResponse struct {
+	TradeOfferID            string `json:"tradeofferid"`
+	NeedsMobileConfirmation bool   `json:"needs_mobile_confirmation"`
+	NeedsEmailConfirmation  bool   `json:"needs_email_confirmation"`
+	EmailDomain             string `json:"email_domain"`
+	StrError                string `json:"strError"`
+}
+
+// AcceptTradeOfferResponse tradeoffer/{id}/accept 接口响应
+type AcceptTradeOfferResponse struct {
+	TradeID                 string `json:"tradeid"`
+	NeedsMobileConfirmation bool   `json:"needs_mobile_confirmation"`
+	NeedsEmailConfirmation  bool   `json:"needs_email_confirmation"`
+	EmailDomain             string `json:"email_domain"`
+	StrError                string `json:"strError"`
+}
+
+// TradeOfferActionResult 发送或接受报价的结果
+type TradeOfferActionResult struct {
+	TradeOfferID            string `json:"tradeofferid"`              // 报价ID
+	TradeID                 string `json:"tradeid"`                   // 交易ID（接受报价且无需确认时返回）
+	NeedsMobileConfirmation bool   `json:"needs_mobile_confirmation"` // 是否需要手机令牌确认
+	NeedsEmailConfirmation  bool   `json:"needs_email_confirmation"`  // 是否需要邮箱确认
+	Confirmed               bool   `json:"confirmed"`                 // 是否已通过令牌文件自动确认
+}
+
+// TradeOfferAsset 报价中的物品
+type TradeOfferAsset struct {
+	AppID       int          `json:"appid"`
+	ContextID   string       `json:"contextid"`
+	AssetID     string       `json:"assetid"`
+	ClassID     string       `json:"classid"`
+	InstanceID  string       `json:"instanceid"`
+	Amount      string       `json:"amount"`
+	Missing     bool         `json:"missing"`               // 物品已不在原库存中
+	Description *Description `json:"description,omitempty"` // 物品描述（查询时请求了描述才会填充）
+}
+
+// TradeOffer 交易报价
+type TradeOffer struct {
+	TradeOfferID       string                       `json:"tradeofferid"`
+	AccountIDOther     uint32                       `json:"accountid_other"`      // 对方 AccountID
+	Message            string                       `json:"message"`              // 报价留言
+	ExpirationTime     int64                        `json:"expiration_time"`      // 过期时间戳
+	State              TradeOfferState              `json:"trade_offer_state"`    // 报价状态
+	ItemsToGive        []TradeOfferAsset            `json:"items_to_give"`        // 我方给出的物品
+	ItemsToReceive     []TradeOfferAsset            `json:"items_to_receive"`     // 我方收到的物品
+	IsOurOffer         bool                         `json:"is_our_offer"`         // 是否由我方发出
+	TimeCreated        int64                        `json:"time_created"`         // 创建时间戳
+	TimeUpdated        int64                        `json:"time_updated"`         // 更新时间戳
+	TradeID            string                       `json:"tradeid"`              // 接受后生成的交易ID
+	FromRealTimeTrade  bool                         `json:"from_real_time_trade"` // 是否来自实时交易
+	EscrowEndDate      int64                        `json:"escrow_end_date"`      // 交易暂挂结束时间戳
+	ConfirmationMethod TradeOfferConfirmationMethod `json:"confirmation_method"`  // 二次确认方式
+}
+
+// PartnerSteamID 对方的 64 位 SteamID
+func (o TradeOffer) PartnerSteamID() uint64 {
+	return uint64(o.AccountIDOther) + 76561197960265728
+}
+
+// ExpiresAt 报价过期时间
+func (o TradeOffer) ExpiresAt() time.Time {
+	return time.Unix(o.ExpirationTime, 0)
+}
+
+// UpdatedAt 报价最后更新时间
+func (o TradeOffer) UpdatedAt() time.Time {
+	return time.Unix(o.TimeUpdated, 0)
+}
+
+// TradeOffersQuery 查询报价列表的条件
+type TradeOffersQuery struct {
+	Sent             bool      // 查询发出的报价
+	Received         bool      // 查询收到的报价
+	ActiveOnly       bool      // 只查询进行中的报价
+	HistoricalOnly   bool      // 只查询历史报价
+	HistoricalCutoff time.Time // 历史报价的起始时间（配合 ActiveOnly 使用，返回此时间之后变化的非进行中报价）
+	Language         string    // 描述语言，默认为 english
+}
+
+// TradeOffersResponse IEconService/GetTradeOffers 接口响应
+type TradeOffersResponse struct {
+	Response struct {
+		TradeOffersSent     []TradeOffer  `json:"trade_offers_sent"`
+		TradeOffersReceived []TradeOffer  `json:"trade_offers_received"`
+		Descriptions        []Description `json:"descriptions"`
+		NextCursor          int           `json:"next_cursor"`
+	} `json:"response"`
+}
+
+// TradeOfferResponse IEconService/GetTradeOffer 接口响应
+type TradeOfferResponse struct {
+	Response struct {
+		Offer        *TradeOffer   `json:"offer"`
+		Descriptions []Description `json:"descriptions"`
+	} `json:"response"`
+}
+
+// TradeOffers 报价列表
+type TradeOffers struct {
+	Sent     []TradeOffer `json:"sent"`     // 发出的报价
+	Received []TradeOffer `json:"received"` // 收到的报价
+}
+
+// TradeHoldDurationsResponse IEconService/GetTradeHoldDurations 接口响应
+type TradeHoldDurationsResponse struct {
+	Response struct {
+		MyEscrow    tradeEscrowDuration `json:"my_escrow"`
+		TheirEscrow tradeEscrowDuration `json:"their_escrow"`
+		BothEscrow  tradeEscrowDuration `json:"both_escrow"`
+	} `json:"response"`
+}
+
+type tradeEscrowDuration struct {
+	EscrowEndDurationSeconds int64 `json:"escrow_end_duration_seconds"`
+}
+
+// TradeHoldDurations 与对方交易时的暂挂时长
+type TradeHoldDurations struct {
+	My    time.Duration `json:"my"`    // 我方物品的暂挂时长
+	Their time.Duration `json:"their"` // 对方物品的暂挂时长
+	Both  time.Duration `json:"both"`  // 双方均给出物品时的暂挂时长
+}
+
+// HasHold 交易是否会被暂挂
+func (t TradeHoldDurations) HasHold() bool {
+	return t.My > 0 || t.Their > 0 || t.Both > 0
+}
+
+// TradeOfferEventType 报价事件类型
+type TradeOfferEventType string
+
+const (
+	TradeOfferEventNewReceived       TradeOfferEventType = "new_received"       // 收到新报价
+	TradeOfferEventAccepted          TradeOfferEventType = "accepted"           // 报价已被接受
+	TradeOfferEventDeclined          TradeOfferEventType = "declined"           // 报价已被拒绝
+	TradeOfferEventCanceled          TradeOfferEventType = "canceled"           // 报价已被取消
+	TradeOfferEventExpired           TradeOfferEventType = "expired"            // 报价已过期
+	TradeOfferEventNeedsConfirmation TradeOfferEventType = "needs_confirmation" // 报价等待二次确认
+	TradeOfferEventInEscrow          TradeOfferEventType = "in_escrow"          // 交易进入暂挂
+	TradeOfferEventItemsReceived     TradeOfferEventType = "items_received"     // 交易完成并收到物品
+	TradeOfferEventStateChanged      TradeOfferEventType = "state_changed"      // 其他状态变化（还价、物品失效等）
+)
+
+// TradeOfferEvent 报价状态变化事件
+type TradeOfferEvent struct {
+	Type     TradeOfferEventType `json:"type"`      // 事件类型
+	Offer    TradeOffer          `json:"offer"`     // 报价最新数据
+	OldState TradeOfferState     `json:"old_state"` // 变化前的状态，首次发现的报价为 0
+}
+
+// TradeStatus 已完成交易的状态（ETradeStatus）
+type TradeStatus int
+
+const (
+	TradeStatusInit                     TradeStatus = 0  // 初始化
+	TradeStatusPreCommitted             TradeStatus = 1  // 预提交
+	TradeStatusCommitted                TradeStatus = 2  // 已提交
+	TradeStatusComplete                 TradeStatus = 3  // 已完成
+	TradeStatusFailed                   TradeStatus = 4  // 失败
+	TradeStatusPartialSupportRollback   TradeStatus = 5  // 客服部分回滚
+	TradeStatusFullSupportRollback      TradeStatus = 6  // 客服完全回滚
+	TradeStatusSupportRollbackSelective TradeStatus = 7  // 客服选择性回滚
+	TradeStatusRollbackFailed           TradeStatus = 8  // 回滚失败
+	TradeStatusRollbackAbandoned        TradeStatus = 9  // 放弃回滚
+	TradeStatusInEscrow                 TradeStatus = 10 // 暂挂中
+	TradeStatusEscrowRollback           TradeStatus = 11 // 暂挂期间被回滚
+)
+
+// pendingTradeStatuses 尚未结束、之后状态还会变化的交易状态
+var pendingTradeStatuses = []TradeStatus{TradeStatusInit, TradeStatusPreCommitted, TradeStatusCommitted, TradeStatusInEscrow}
+
+// PendingTradeStatuses 返回尚未结束的交易状态
+func PendingTradeStatuses() []TradeStatus {
+	return append([]TradeStatus(nil), pendingTradeStatuses...)
+}
+
+// IsPending 交易是否尚未结束（例如处于暂挂中）
+func (s TradeStatus) IsPending() bool {
+	for _, status := range pendingTradeStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// TradeHistoryAsset 交易历史中的物品，交易后物品会获得新的资产ID
+type TradeHistoryAsset struct {
+	AppID        int          `json:"appid"`
+	ContextID    string       `json:"contextid"`
+	AssetID      string       `json:"assetid"` // 交易前的资产ID
+	Amount       string       `json:"amount"`
+	ClassID      string       `json:"classid"`
+	InstanceID   string       `json:"instanceid"`
+	NewAssetID   string       `json:"new_assetid"`           // 交易后在对方库存中的资产ID
+	NewContextID string       `json:"new_contextid"`         // 交易后的库存上下文ID
+	Description  *Description `json:"description,omitempty"` // 物品描述
+}
+
+// TradeHistoryTrade 一笔已发生的交易
+type TradeHistoryTrade struct {
+	TradeID        string              `json:"tradeid"`
+	SteamIDOther   string              `json:"steamid_other"`   // 对方 SteamID
+	TimeInit       int64               `json:"time_init"`       // 交易发起时间戳
+	TimeEscrowEnd  int64               `json:"time_escrow_end"` // 暂挂结束时间戳
+	Status         TradeStatus         `json:"status"`          // 交易状态
+	AssetsGiven    []TradeHistoryAsset `json:"assets_given"`    // 我方给出的物品
+	AssetsReceived []TradeHistoryAsset `json:"assets_received"` // 我方收到的物品
+}
+
+// TradeHistoryQuery 查询交易历史的条件
+type TradeHistoryQuery struct {
+	MaxTrades         int       // 每页数量，默认 100
+	StartAfterTime    time.Time // 从该时间之后（更早）开始查询，用于翻页
+	StartAfterTradeID string    // 与 StartAfterTime 配合使用的上一页最后一笔交易ID
+	NavigatingBack    bool      // 向更新的交易方向翻页
+	IncludeFailed     bool      // 包含失败的交易
+	Language          string    // 描述语言，默认为 english
+}
+
+// TradeHistoryResponse IEconService/GetTradeHistory 接口响应
+type TradeHistoryResponse struct {
+	Response struct {
+		TotalTrades  int                 `json:"total_trades"`
+		More         bool                `json:"more"`
+		Trades       []TradeHistoryTrade `json:"trades"`
+		Descriptions []Description       `json:"descriptions"`
+	} `json:"response"`
+}
+
+// TradeHistoryPage 一页交易历史
+type TradeHistoryPage struct {
+	TotalTrades int                 `json:"total_trades"` // 交易总数
+	More        bool                `json:"more"`         // 是否还有下一页
+	Trades      []TradeHistoryTrade `json:"trades"`       // 交易列表（按时间从新到旧）
+}
+
+// AssetProvenanceHop 物品经过的一次交易
+type AssetProvenanceHop struct {
+	TradeID     string    `json:"tradeid"`      // 交易ID
+	TimeInit    time.Time `json:"time_init"`    // 交易时间
+	FromSteamID uint64    `json:"from_steamid"` // 给出物品的账号
+	ToSteamID   uint64    `json:"to_steamid"`   // 收到物品的账号
+	AssetID     string    `json:"assetid"`      // 交易前的资产ID
+	NewAssetID  string    `json:"new_assetid"`  // 交易后的资产ID
+}
+
+// AssetProvenance 物品的来源追溯结果
+type AssetProvenance struct {
+	AppID        int                  `json:"appid"`
+	AssetID      string               `json:"assetid"`       // 查询的资产ID
+	AssetIDs     []string             `json:"assetids"`      // 物品历史上使用过的全部资产ID（从新到旧）
+	Trades       []AssetProvenanceHop `json:"trades"`        // 经过的交易（从新到旧）
+	MarketEvents []MarketHistoryEvent `json:"market_events"` // 与这些资产ID相关的市场交易历史
+}
@@ -13,6 +13,7 @@ import (
 	"maps"
 	"net/http"
 	"net/url"
+	"reflect"
 	"slices"
 	"sort"
 	"strconv"
@@ -287,3 +288,52 @@ func (p Params) SetParams(values url.Values) {
 		p[k] = v[0]
 	}
 }
+
+// EncodeForm 根据结构体字段的 form 标签生成请求参数
+// 支持字符串、整数、浮点数和布尔值（编码为 1/0）字段，没有 form 标签或标签为 "-" 的字段会被忽略
+// 参数：v - 结构体或结构体指针
+// 返回值：请求参数，遇到不支持的字段类型时返回错误
+func EncodeForm(v any) (Params, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, errors.New("EncodeForm: 参数为空指针")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("EncodeForm: 不支持的参数类型 %s", rv.Type())
+	}
+
+	params := Params{}
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := field.Tag.Get("form")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+
+		value := rv.Field(i)
+		switch value.Kind() {
+		case reflect.String:
+			params.SetString(name, value.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			params.SetInt64(name, value.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			params.SetString(name, strconv.FormatUint(value.Uint(), 10))
+		case reflect.Float32, reflect.Float64:
+			params.SetString(name, strconv.FormatFloat(value.Float(), 'f', -1, 64))
+		case reflect.Bool:
+			if value.Bool() {
+				params.SetInt64(name, 1)
+			} else {
+				params.SetInt64(name, 0)
+			}
+		default:
+			return nil, fmt.Errorf("EncodeForm: 字段 %s 的类型 %s 不支持", field.Name, value.Type())
+		}
+	}
+
+	return params, nil
+}
@@ -1758,6 +1758,141 @@ func (x *RemoveItem) GetUserCountry() string {
 	return ""
 }
 
+// 获取购物车
+type GetCartSend struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserCountry   string                 `protobuf:"bytes,1,opt,name=user_country,json=userCountry,proto3" json:"user_country,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCartSend) Reset() {
+	*x = GetCartSend{}
+	mi := &file_cart_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCartSend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCartSend) ProtoMessage() {}
+
+func (x *GetCartSend) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCartSend.ProtoReflect.Descriptor instead.
+func (*GetCartSend) Descriptor() ([]byte, []int) {
+	return file_cart_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetCartSend) GetUserCountry() string {
+	if x != nil {
+		return x.UserCountry
+	}
+	return ""
+}
+
+// 获取购物车返回值
+type GetCartReceive struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cart          *Cart                  `protobuf:"bytes,1,opt,name=cart,proto3" json:"cart,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCartReceive) Reset() {
+	*x = GetCartReceive{}
+	mi := &file_cart_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCartReceive) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCartReceive) ProtoMessage() {}
+
+func (x *GetCartReceive) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCartReceive.ProtoReflect.Descriptor instead.
+func (*GetCartReceive) Descriptor() ([]byte, []int) {
+	return file_cart_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetCartReceive) GetCart() *Cart {
+	if x != nil {
+		return x.Cart
+	}
+	return nil
+}
+
+// 删除返回值
+type RemoveCartReceive struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cart          *Cart                  `protobuf:"bytes,1,opt,name=cart,proto3" json:"cart,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveCartReceive) Reset() {
+	*x = RemoveCartReceive{}
+	mi := &file_cart_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveCartReceive) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveCartReceive) ProtoMessage() {}
+
+func (x *RemoveCartReceive) ProtoReflect() protoreflect.Message {
+	mi := &file_cart_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveCartReceive.ProtoReflect.Descriptor instead.
+func (*RemoveCartReceive) Descriptor() ([]byte, []int) {
+	return file_cart_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RemoveCartReceive) GetCart() *Cart {
+	if x != nil {
+		return x.Cart
+	}
+	return nil
+}
+
 var File_cart_proto protoreflect.FileDescriptor
 
 const file_cart_proto_rawDesc = "" +
@@ -1921,7 +2056,13 @@ const file_cart_proto_rawDesc = "" +
 	"RemoveItem\x12 \n" +
 	"\fline_item_id\x18\x01 \x01(\x04R\n" +
 	"lineItemId\x12!\n" +
-	"\fuser_country\x18\x02 \x01(\tR\vuserCountryB\vZ\t../Protocb\x06proto3"
+	"\fuser_country\x18\x02 \x01(\tR\vuserCountry\"0\n" +
+	"\vGetCartSend\x12!\n" +
+	"\fuser_country\x18\x01 \x01(\tR\vuserCountry\"1\n" +
+	"\x0eGetCartReceive\x12\x1f\n" +
+	"\x04cart\x18\x01 \x01(\v2\v.steam.CartR\x04cart\"4\n" +
+	"\x11RemoveCartReceive\x12\x1f\n" +
+	"\x04cart\x18\x01 \x01(\v2\v.steam.CartR\x04cartB\vZ\t../Protocb\x06proto3"
 
 var (
 	file_cart_proto_rawDescOnce sync.Once
@@ -1935,7 +2076,7 @@ func file_cart_proto_rawDescGZIP() []byte {
 	return file_cart_proto_rawDescData
 }
 
-var file_cart_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_cart_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_cart_proto_goTypes = []any{
 	(*AddCartSend)(nil),       // 0: steam.AddCartSend
 	(*Item)(nil),              // 1: steam.Item
@@ -1960,7 +2101,10 @@ var file_cart_proto_goTypes = []any{
 	(*RemoveCartSend)(nil),    // 20: steam.RemoveCartSend
 	(*ItemDataRequest)(nil),   // 21: steam.ItemDataRequest
 	(*RemoveItem)(nil),        // 22: steam.RemoveItem
-	(*StoreItem)(nil),         // 23: steam.StoreItem
+	(*GetCartSend)(nil),       // 23: steam.GetCartSend
+	(*GetCartReceive)(nil),    // 24: steam.GetCartReceive
+	(*RemoveCartReceive)(nil), // 25: steam.RemoveCartReceive
+	(*StoreItem)(nil),         // 26: steam.StoreItem
 }
 var file_cart_proto_depIdxs = []int32{
 	1,  // 0: steam.AddCartSend.items:type_name -> steam.Item
@@ -1983,7 +2127,7 @@ var file_cart_proto_depIdxs = []int32{
 	15, // 17: steam.CartInfo.cart_items:type_name -> steam.CartItem
 	19, // 18: steam.CartInfo.estimated_totals:type_name -> steam.Estimate
 	18, // 19: steam.CartItem.item_id:type_name -> steam.ItemID
-	23, // 20: steam.CartItem.store_item:type_name -> steam.StoreItem
+	26, // 20: steam.CartItem.store_item:type_name -> steam.StoreItem
 	4,  // 21: steam.CartItem.gift_info:type_name -> steam.GiftInfo
 	13, // 22: steam.CartItem.subtotal:type_name -> steam.Price
 	13, // 23: steam.CartItem.price_when_added:type_name -> steam.Price
@@ -1997,11 +2141,13 @@ var file_cart_proto_depIdxs = []int32{
 	13, // 31: steam.Estimate.exceeding_wallet_balance:type_name -> steam.Price
 	13, // 32: steam.Estimate.remaining_wallet_balance:type_name -> steam.Price
 	21, // 33: steam.ItemDataRequest.included_item_data_request:type_name -> steam.ItemDataRequest
-	34, // [34:34] is the sub-list for method output_type
-	34, // [34:34] is the sub-list for method input_type
-	34, // [34:34] is the sub-list for extension type_name
-	34, // [34:34] is the sub-list for extension extendee
-	0,  // [0:34] is the sub-list for field type_name
+	10, // 34: steam.GetCartReceive.cart:type_name -> steam.Cart
+	10, // 35: steam.RemoveCartReceive.cart:type_name -> steam.Cart
+	36, // [36:36] is the sub-list for method output_type
+	36, // [36:36] is the sub-list for method input_type
+	36, // [36:36] is the sub-list for extension type_name
+	36, // [36:36] is the sub-list for extension extendee
+	0,  // [0:36] is the sub-list for field type_name
 }
 
 func init() { file_cart_proto_init() }
@@ -2016,7 +2162,7 @@ func file_cart_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cart_proto_rawDesc), len(file_cart_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   23,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
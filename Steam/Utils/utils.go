@@ -8,10 +8,10 @@ import (
 	"math/big"
 	"os"
 	"os/exec"
-	"regexp"
 	"runtime"
-	"strconv"
 	"strings"
+
+	"github.com/JovanniChen/SteamDB/Steam/Model"
 )
 
 // OpenFormInBrowser creates a temporary HTML file with a self-submitting form
@@ -19,22 +19,7 @@ import (
 // that might trigger on direct programmatic POST requests.
 func OpenFormInBrowser(actionURL string, formData map[string]string) error {
 	// 1. Build the HTML content for the form.
-	var htmlBuilder strings.Builder
-	htmlBuilder.WriteString("<!DOCTYPE html><html><head><title>Redirecting...</title>")
-	htmlBuilder.WriteString("<style>body { font-family: sans-serif; display: flex; justify-content: center; align-items: center; height: 100vh; margin: 0; } .container { text-align: center; } h1 { color: #333; } p { color: #666; }</style>")
-	htmlBuilder.WriteString("</head><body><div class=\"container\">")
-	htmlBuilder.WriteString("<h1>Processing your request...</h1>")
-	htmlBuilder.WriteString(fmt.Sprintf("<form id=\"redirectForm\" action=\"%s\" method=\"POST\">", html.EscapeString(actionURL)))
-
-	for key, value := range formData {
-		htmlBuilder.WriteString(fmt.Sprintf("<input type=\"hidden\" name=\"%s\" value=\"%s\">", html.EscapeString(key), html.EscapeString(value)))
-	}
-
-	htmlBuilder.WriteString("<noscript><input type=\"submit\" value=\"Click here to continue\"></noscript>")
-	htmlBuilder.WriteString("</form>")
-	htmlBuilder.WriteString("<p>You are being automatically redirected. If nothing happens, please enable JavaScript or click the button above.</p>")
-	htmlBuilder.WriteString("<script>document.getElementById('redirectForm').submit();</script>")
-	htmlBuilder.WriteString("</div></body></html>")
+	content := AutoSubmitFormHTML(actionURL, "POST", formData)
 
 	// 2. Write the HTML to a temporary file.
 	tmpFile, err := os.CreateTemp("", "steam_redirect_*.html")
@@ -44,7 +29,7 @@ func OpenFormInBrowser(actionURL string, formData map[string]string) error {
 	// Schedule the file for deletion, but also handle immediate errors.
 	defer os.Remove(tmpFile.Name())
 
-	if _, err := tmpFile.WriteString(htmlBuilder.String()); err != nil {
+	if _, err := tmpFile.WriteString(content); err != nil {
 		tmpFile.Close() // Close before returning
 		return fmt.Errorf("failed to write to temporary file: %w", err)
 	}
@@ -85,6 +70,33 @@ func OpenFormInBrowser(actionURL string, formData map[string]string) error {
 	return nil
 }
 
+// AutoSubmitFormHTML builds an HTML page containing a form that submits itself
+// as soon as the page is loaded. method defaults to POST when empty.
+func AutoSubmitFormHTML(actionURL, method string, formData map[string]string) string {
+	if method == "" {
+		method = "POST"
+	}
+
+	var htmlBuilder strings.Builder
+	htmlBuilder.WriteString("<!DOCTYPE html><html><head><title>Redirecting...</title>")
+	htmlBuilder.WriteString("<style>body { font-family: sans-serif; display: flex; justify-content: center; align-items: center; height: 100vh; margin: 0; } .container { text-align: center; } h1 { color: #333; } p { color: #666; }</style>")
+	htmlBuilder.WriteString("</head><body><div class=\"container\">")
+	htmlBuilder.WriteString("<h1>Processing your request...</h1>")
+	htmlBuilder.WriteString(fmt.Sprintf("<form id=\"redirectForm\" action=\"%s\" method=\"%s\">", html.EscapeString(actionURL), html.EscapeString(method)))
+
+	for key, value := range formData {
+		htmlBuilder.WriteString(fmt.Sprintf("<input type=\"hidden\" name=\"%s\" value=\"%s\">", html.EscapeString(key), html.EscapeString(value)))
+	}
+
+	htmlBuilder.WriteString("<noscript><input type=\"submit\" value=\"Click here to continue\"></noscript>")
+	htmlBuilder.WriteString("</form>")
+	htmlBuilder.WriteString("<p>You are being automatically redirected. If nothing happens, please enable JavaScript or click the button above.</p>")
+	htmlBuilder.WriteString("<script>document.getElementById('redirectForm').submit();</script>")
+	htmlBuilder.WriteString("</div></body></html>")
+
+	return htmlBuilder.String()
+}
+
 // SafeHexString 生成安全的十六进制随机字符串
 // 使用加密安全的随机数生成器，适用于密钥、令牌等安全场景
 // 参数：n - 字节长度（最终字符串长度为2*n）
@@ -124,23 +136,11 @@ func SafeCustomString(length int, charset string) string {
 	return string(b)
 }
 
+// WalletConvert 将钱包余额字符串转换为以分为单位的整数
+//
+// Deprecated: 使用 Model.ParseMoney，可正确处理各地区的千位分隔符和小数点
 func WalletConvert(wallet string) int {
-	// 正则表达式匹配数字（整数或小数）
-	re := regexp.MustCompile(`\d+(\.\d+)?`)
-	// 查找所有匹配的数字
-	numbers := re.FindAllString(wallet, -1)
-	// 将所有数字拼接起来
-	moneyStr := strings.Join(numbers, "")
-
-	// 转换为浮点数
-	money, err := strconv.ParseFloat(moneyStr, 64)
-	if err != nil {
-		// 如果转换失败，返回0
-		return 0
-	}
-
-	// 乘以100后转换为整数（以分为单位）
-	return int(money * 100)
+	return int(Model.MustParseMoney(wallet, Model.CurrencyCNY).Amount)
 }
 
 func FriendCodeToSteamID64(friendCode uint32) uint64 {
@@ -4,6 +4,7 @@ package Steam
 
 import (
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/JovanniChen/SteamDB/Steam/Constants"
@@ -315,19 +316,75 @@ func (c *Client) GetInventory(gameID int, categoryId int) ([]Model.Item, error)
 	return c.dao.GetInventory(gameID, categoryId)
 }
 
+// NewInventoryIterator 创建库存迭代器，自动翻页并返回带完整描述的物品
+func (c *Client) NewInventoryIterator(appID int, contextID int) *Dao.InventoryIterator {
+	return c.dao.NewInventoryIterator(appID, contextID)
+}
+
+// GetInventoryItems 获取指定游戏/上下文的全部库存，filters 为可选的过滤条件
+func (c *Client) GetInventoryItems(appID int, contextID int, filters ...Model.InventoryFilter) ([]Model.InventoryItem, error) {
+	return c.dao.GetInventoryItems(appID, contextID, filters...)
+}
+
+// NewUserInventoryIterator 创建任意账号的库存迭代器，对方库存需要公开
+func (c *Client) NewUserInventoryIterator(steamID uint64, appID int, contextID int) *Dao.InventoryIterator {
+	return c.dao.NewUserInventoryIterator(steamID, appID, contextID)
+}
+
+// GetUserInventoryItems 获取任意账号指定游戏/上下文的全部公开库存
+func (c *Client) GetUserInventoryItems(steamID uint64, appID int, contextID int, filters ...Model.InventoryFilter) ([]Model.InventoryItem, error) {
+	return c.dao.GetUserInventoryItems(steamID, appID, contextID, filters...)
+}
+
+// GetInventoryContexts 获取账号拥有库存的游戏及上下文，steamID 为 0 时查询当前账号
+func (c *Client) GetInventoryContexts(steamID uint64) ([]Model.InventoryApp, error) {
+	return c.dao.GetInventoryContexts(steamID)
+}
+
+// RefreshInventorySnapshot 刷新库存快照并返回与上次快照的差异，库存未变化时尽量跳过完整下载
+// steamID 为 0 时刷新当前账号
+func (c *Client) RefreshInventorySnapshot(steamID uint64, appID int, contextID int, opts *Model.InventoryRefreshOptions) (*Model.InventoryDiff, error) {
+	return c.dao.RefreshInventorySnapshot(steamID, appID, contextID, opts)
+}
+
+// GetInventorySnapshot 读取数据库中保存的最新库存快照，没有记录时返回 nil
+func (c *Client) GetInventorySnapshot(steamID uint64, appID int, contextID int) (*Model.InventorySnapshot, error) {
+	return Dao.GetInventorySnapshot(steamID, appID, contextID, true)
+}
+
 func (c *Client) GetSteamGift(gameID int, categoryId int) ([]Model.Item, error) {
 	return c.dao.GetSteamGift(gameID, categoryId)
 }
 
-func (c *Client) PutList(gameid int, contextId int, assetID string, price float64, currency int, maFileContent string) (Model.MyListingReponse, error) {
-	return c.dao.PutList(gameid, contextId, assetID, price, currency, maFileContent)
+func (c *Client) PutList(gameid int, contextId int, assetID string, price Model.Money, maFileContent string) (Model.MyListingReponse, error) {
+	return c.dao.PutList(gameid, contextId, assetID, price, maFileContent)
 }
 
-func (c *Client) BuyListing(gameId, creatorId string, name string, buyerPrice float64, sellerReceivePrice float64, maFileContent string) error {
-	return c.dao.BuyListing(gameId, creatorId, name, buyerPrice, sellerReceivePrice, "0", maFileContent)
+func (c *Client) BuyListing(gameId, creatorId string, name string, buyerPrice Model.Money, maFileContent string) error {
+	return c.dao.BuyListing(gameId, creatorId, name, buyerPrice, "0", maFileContent)
 }
 
-func (c *Client) CreateOrder(marketHashName string, price float64, quantity int64, maFileContent string) error {
+// FetchWalletInfo 从社区市场获取钱包信息（手续费比例、余额等），获取后用于计算手续费
+func (c *Client) FetchWalletInfo() (*Model.WalletInfo, error) {
+	return c.dao.FetchWalletInfo()
+}
+
+// SetPublisherFeePercent 为指定游戏设置发行商手续费比例
+func (c *Client) SetPublisherFeePercent(appID int, percent float64) {
+	c.dao.SetPublisherFeePercent(appID, percent)
+}
+
+// SellerReceiveToBuyerPrice 根据卖家到手金额计算买家支付价格
+func (c *Client) SellerReceiveToBuyerPrice(appID int, sellerReceive Model.Money) Model.MarketFee {
+	return c.dao.SellerReceiveToBuyerPrice(appID, sellerReceive)
+}
+
+// BuyerPriceToSellerReceive 根据买家支付价格计算卖家到手金额和手续费
+func (c *Client) BuyerPriceToSellerReceive(appID int, buyerPrice Model.Money) Model.MarketFee {
+	return c.dao.BuyerPriceToSellerReceive(appID, buyerPrice)
+}
+
+func (c *Client) CreateOrder(marketHashName string, price Model.Money, quantity int64, maFileContent string) error {
 	return c.dao.CreateOrder(marketHashName, price, quantity, maFileContent)
 }
 
@@ -335,14 +392,61 @@ func (c *Client) RemoveMyListings(creatorId string) error {
 	return c.dao.RemoveMyListings(creatorId)
 }
 
-func (c *Client) RemoveAllMyListings() error {
-	return c.dao.RemoveAllMyListings()
+// RemoveAllMyListings 批量下架已上架和等待确认的物品，opts 为 nil 时下架全部
+func (c *Client) RemoveAllMyListings(opts *Model.RemoveListingsOptions) (int, error) {
+	return c.dao.RemoveAllMyListings(opts)
+}
+
+// CancelBuyOrder 取消由 CreateOrder 创建的订购单
+func (c *Client) CancelBuyOrder(orderID string) error {
+	return c.dao.CancelBuyOrder(orderID)
+}
+
+// PlaceBuyOrder 创建订购单并返回订购单ID，price 为单价
+func (c *Client) PlaceBuyOrder(appID int, marketHashName string, price Model.Money, quantity int64, maFileContent string) (string, error) {
+	return c.dao.PlaceBuyOrder(appID, marketHashName, price, quantity, maFileContent)
+}
+
+// GetBuyOrderStatus 查询订购单的成交进度
+func (c *Client) GetBuyOrderStatus(buyOrderID string) (*Model.BuyOrderStatus, error) {
+	return c.dao.GetBuyOrderStatus(buyOrderID)
 }
 
-func (c *Client) GetMyListings() (activeListings []Model.MyListingReponse, err error) {
+// GetMarketHistory 获取市场交易历史，count 超过单页上限时自动翻页
+func (c *Client) GetMarketHistory(start, count int) (*Model.MarketHistoryPage, error) {
+	return c.dao.GetMarketHistory(start, count)
+}
+
+// SyncMarketHistory 增量同步市场交易历史到数据库，返回新增的事件
+func (c *Client) SyncMarketHistory() ([]Model.MarketHistoryEvent, error) {
+	return c.dao.SyncMarketHistory()
+}
+
+// GetStoredMarketHistory 读取数据库中当前账号已同步的市场交易历史
+func (c *Client) GetStoredMarketHistory() ([]Model.MarketHistoryEvent, error) {
+	return Dao.GetMarketHistoryEvents(c.dao.GetSteamID())
+}
+
+// ExportMarketHistoryCSV 将交易历史导出为CSV
+func (c *Client) ExportMarketHistoryCSV(w io.Writer, events []Model.MarketHistoryEvent) error {
+	return Dao.ExportMarketHistoryCSV(w, events)
+}
+
+// ExportMarketHistoryJSON 将交易历史导出为JSON
+func (c *Client) ExportMarketHistoryJSON(w io.Writer, events []Model.MarketHistoryEvent) error {
+	return Dao.ExportMarketHistoryJSON(w, events)
+}
+
+// GetMyListings 获取当前用户的全部已上架物品、等待确认物品和订购单
+func (c *Client) GetMyListings() (*Model.MyListings, error) {
 	return c.dao.GetMyListings()
 }
 
+// RemovePendingListings 删除所有等待确认的上架物品，返回已删除的 Listing ID
+func (c *Client) RemovePendingListings() ([]string, error) {
+	return c.dao.RemovePendingListings()
+}
+
 func (c *Client) GetConfirmations(maFileContent string) error {
 	return c.dao.GetConfirmations(maFileContent)
 }
@@ -429,15 +533,15 @@ func (c *Client) GetLoginCookies() map[string]*Dao.LoginCookie {
 	return c.dao.GetLoginCookies()
 }
 
-func (c *Client) GetBalance() int {
+func (c *Client) GetBalance() Model.Money {
 	return c.dao.GetBalance()
 }
 
-func (c *Client) GetWaitBalance() int {
+func (c *Client) GetWaitBalance() Model.Money {
 	return c.dao.GetWaitBalance()
 }
 
-func (c *Client) GetBalanceAndWaitBalance() (int, int) {
+func (c *Client) GetBalanceAndWaitBalance() (Model.Money, Model.Money) {
 	return c.dao.GetBalanceAndWaitBalance()
 }
 
@@ -472,10 +576,21 @@ func (c *Client) ClearCart() error {
 	return c.dao.ClearCart()
 }
 
-func (c *Client) GetCart() error {
+// GetCart 获取购物车内容（商品、小计和校验结果）
+func (c *Client) GetCart() (*Protoc.Cart, error) {
 	return c.dao.GetCart()
 }
 
+// ModifyLineItem 修改购物车中商品的礼物信息、私密标记和优惠券
+func (c *Client) ModifyLineItem(lineItemID uint64, item Model.ModifyLineItem) (*Protoc.Cart, error) {
+	return c.dao.ModifyLineItem(lineItemID, item)
+}
+
+// RemoveLineItem 从购物车中删除商品
+func (c *Client) RemoveLineItem(lineItemID uint64) (*Protoc.Cart, error) {
+	return c.dao.RemoveLineItem(lineItemID)
+}
+
 func (c *Client) AddItemToCart(addCartItems []Model.AddCartItem) error {
 	return c.dao.AddItemToCart(addCartItems)
 }
@@ -496,6 +611,20 @@ func (c *Client) InitConcurrentTransaction() (string, error) {
 	return result, err
 }
 
+// InitTransactionWithOptions 按支付方式、账单地区和礼物选项初始化交易
+func (c *Client) InitTransactionWithOptions(opts *Model.TransactionOptions) (string, error) {
+	result, err := c.dao.InitTransactionWithOptions(opts)
+	if err != nil {
+		err = fmt.Errorf("初始化交易失败,代理: %s,错误: %w", c.dao.GetProxy(), err)
+	}
+	return result, err
+}
+
+// CheckoutWithWallet 使用 Steam 钱包余额购买购物车中的商品，余额不足时返回 *Errors.InsufficientFundsError
+func (c *Client) CheckoutWithWallet(opts *Model.WalletCheckoutOptions) (*Model.WalletCheckout, error) {
+	return c.dao.CheckoutWithWallet(opts)
+}
+
 func (c *Client) FinalizeTransaction(transactionID string) error {
 	err := c.dao.FinalizeTransaction(transactionID)
 	if err != nil {
@@ -524,6 +653,16 @@ func (c *Client) GetAlipayURL(transactionID string) (string, error) {
 	return c.dao.GetAlipayURL(transactionID)
 }
 
+// GetPaymentHandoff 获取交易的外部支付表单和支付跳转链接，可在其他设备上完成付款
+func (c *Client) GetPaymentHandoff(transactionID string) (*Model.PaymentHandoff, error) {
+	return c.dao.GetPaymentHandoff(transactionID)
+}
+
+// NewPaymentHandoffServer 创建通过一次性链接分享支付交接信息的 HTTP 服务
+func (c *Client) NewPaymentHandoffServer(opts *Model.PaymentHandoffServerOptions) *Dao.PaymentHandoffServer {
+	return Dao.NewPaymentHandoffServer(opts)
+}
+
 func (c *Client) UnsendGift(giftId string) error {
 	return c.dao.UnsendGift(giftId)
 }
@@ -552,10 +691,16 @@ func (c *Client) TransactionStatus(transId string, count int) error {
 	return c.dao.TransactionStatus(transId, count)
 }
 
-func (c *Client) ValidateCart() error {
+// ValidateCart 校验购物车，返回每个商品无法购买的原因
+func (c *Client) ValidateCart() (*Model.CartValidation, error) {
 	return c.dao.ValidateCart()
 }
 
+// RemoveInvalidLineItems 从购物车中删除会导致结算失败的商品
+func (c *Client) RemoveInvalidLineItems(validation *Model.CartValidation) (*Protoc.Cart, error) {
+	return c.dao.RemoveInvalidLineItems(validation)
+}
+
 func (c *Client) GetProductByAppUrl(url string) ([]Model.GamePurchaseAction, error) {
 	return c.dao.GetProductByAppUrl(url)
 }
@@ -563,3 +708,133 @@ func (c *Client) GetProductByAppUrl(url string) ([]Model.GamePurchaseAction, err
 func (c *Client) GetProductByAppID(appID int) (map[string]Model.GamePurchaseAction, error) {
 	return c.dao.GetProductByAppID(appID)
 }
+
+// ParseTradeURL 解析交易链接
+func (c *Client) ParseTradeURL(tradeURL string) (*Model.TradeURL, error) {
+	return Dao.ParseTradeURL(tradeURL)
+}
+
+// SendTradeOffer 通过交易链接发送报价，需要确认时使用令牌文件自动确认
+func (c *Client) SendTradeOffer(tradeURL string, itemsToGive []Model.TradeOfferItem, itemsToReceive []Model.TradeOfferItem, message string, maFileContent string) (*Model.TradeOfferActionResult, error) {
+	return c.dao.SendTradeOffer(tradeURL, itemsToGive, itemsToReceive, message, maFileContent)
+}
+
+// AcceptTradeOffer 接受报价，需要确认时使用令牌文件自动确认
+func (c *Client) AcceptTradeOffer(tradeOfferID string, maFileContent string) (*Model.TradeOfferActionResult, error) {
+	return c.dao.AcceptTradeOffer(tradeOfferID, maFileContent)
+}
+
+// DeclineTradeOffer 拒绝收到的报价
+func (c *Client) DeclineTradeOffer(tradeOfferID string) error {
+	return c.dao.DeclineTradeOffer(tradeOfferID)
+}
+
+// CancelTradeOffer 取消发出的报价
+func (c *Client) CancelTradeOffer(tradeOfferID string) error {
+	return c.dao.CancelTradeOffer(tradeOfferID)
+}
+
+// GetTradeHoldDurations 查询与对方交易时双方的暂挂时长，tradeToken 为交易链接中的 token
+func (c *Client) GetTradeHoldDurations(partnerSteamID uint64, tradeToken string) (*Model.TradeHoldDurations, error) {
+	return c.dao.GetTradeHoldDurations(partnerSteamID, tradeToken)
+}
+
+// ConfirmTradeOffer 使用令牌文件确认报价
+func (c *Client) ConfirmTradeOffer(tradeOfferID string, maFileContent string) error {
+	return c.dao.ConfirmTradeOffer(tradeOfferID, maFileContent)
+}
+
+// GetTradeOffers 获取报价列表
+func (c *Client) GetTradeOffers(query *Model.TradeOffersQuery) (*Model.TradeOffers, error) {
+	return c.dao.GetTradeOffers(query)
+}
+
+// GetTradeOffer 获取单个报价详情
+func (c *Client) GetTradeOffer(tradeOfferID string) (*Model.TradeOffer, error) {
+	return c.dao.GetTradeOffer(tradeOfferID)
+}
+
+// NewTradeOfferManager 创建报价管理器，调用 Start 后定时轮询报价并通过 handler 通知状态变化
+func (c *Client) NewTradeOfferManager(interval time.Duration, handler Dao.TradeOfferHandler) *Dao.TradeOfferManager {
+	return c.dao.NewTradeOfferManager(interval, handler)
+}
+
+// GetStoredTradeOffers 获取数据库中保存的当前账号报价
+func (c *Client) GetStoredTradeOffers() ([]Model.TradeOffer, error) {
+	return Dao.GetStoredTradeOffers(c.dao.GetSteamID())
+}
+
+// GetTradeHistory 获取一页交易历史
+func (c *Client) GetTradeHistory(query *Model.TradeHistoryQuery) (*Model.TradeHistoryPage, error) {
+	return c.dao.GetTradeHistory(query)
+}
+
+// SyncTradeHistory 增量同步交易历史到数据库，返回新增的交易
+func (c *Client) SyncTradeHistory(includeFailed bool) ([]Model.TradeHistoryTrade, error) {
+	return c.dao.SyncTradeHistory(includeFailed)
+}
+
+// GetStoredTradeHistory 获取数据库中保存的当前账号交易历史
+func (c *Client) GetStoredTradeHistory() ([]Model.TradeHistoryTrade, error) {
+	return Dao.GetStoredTradeHistory(c.dao.GetSteamID())
+}
+
+// GetAssetProvenance 根据数据库中所有账号的交易历史追溯物品来源，并关联市场交易历史
+func (c *Client) GetAssetProvenance(appID int, assetID string) (*Model.AssetProvenance, error) {
+	return Dao.GetAssetProvenance(appID, assetID)
+}
+
+// SellItems 批量上架物品，全部提交后统一批量确认，返回每个物品的结果
+func (c *Client) SellItems(orders []Model.SellOrder, opts *Model.SellOptions, maFileContent string) (*Model.SellReport, error) {
+	return c.dao.SellItems(orders, opts, maFileContent)
+}
+
+// GetOrderBook 获取物品的订单簿
+func (c *Client) GetOrderBook(appID int, marketHashName string) (*Model.OrderBook, error) {
+	return c.dao.GetOrderBook(appID, marketHashName)
+}
+
+// NewRepricer 创建自动改价器，可调用 RunOnce 执行一轮或 Start 后台定期执行
+func (c *Client) NewRepricer(strategy Dao.RepriceStrategy, opts *Model.RepricerOptions) *Dao.Repricer {
+	return c.dao.NewRepricer(strategy, opts)
+}
+
+// NewBuyOrderManager 创建订购单管理器，可调用 Poll 查询成交进度或 Start 后台定期查询
+func (c *Client) NewBuyOrderManager(opts *Model.BuyOrderManagerOptions) *Dao.BuyOrderManager {
+	return c.dao.NewBuyOrderManager(opts)
+}
+
+// GetStoreItems 批量获取商店物品详情，返回按游戏、礼包、捆绑包分组的结果
+func (c *Client) GetStoreItems(ids []Model.StoreItemID, country, language string, dataRequest *Protoc.ItemDataRequest) (*Model.StoreItems, error) {
+	return c.dao.GetStoreItems(ids, country, language, dataRequest)
+}
+
+// CompareStorePrices 匿名查询物品在多个地区的价格，返回每个物品的地区价格对比
+func (c *Client) CompareStorePrices(ids []Model.StoreItemID, countries []string) ([]Model.PriceComparison, error) {
+	return c.dao.CompareStorePrices(ids, countries)
+}
+
+// NewPriceWatcher 创建价格监控，可调用 Check 立即检查或 Start 后台定期检查
+func (c *Client) NewPriceWatcher(notifier Dao.PriceNotifier, opts *Model.PriceWatcherOptions) *Dao.PriceWatcher {
+	return c.dao.NewPriceWatcher(notifier, opts)
+}
+
+// GetStorePriceHistory 获取物品在某个地区的价格记录
+func (c *Client) GetStorePriceHistory(item Model.StoreItemID, countryCode string) ([]Model.StorePriceRecord, error) {
+	return Dao.GetStorePriceHistory(item, countryCode)
+}
+
+// StartPurchase 执行完整的购买流程（购物车 → 交易 → 付款 → 交易状态），进度保存在数据库中
+func (c *Client) StartPurchase(items []Model.AddCartItem, opts *Model.PurchaseOptions) (*Model.Purchase, error) {
+	return c.dao.StartPurchase(items, opts)
+}
+
+// ResumePurchase 继续中断的购买流程
+func (c *Client) ResumePurchase(purchaseID int64, opts *Model.PurchaseOptions) (*Model.Purchase, error) {
+	return c.dao.ResumePurchase(purchaseID, opts)
+}
+
+// GetUnfinishedPurchases 获取未完成的购买流程
+func (c *Client) GetUnfinishedPurchases() ([]Model.Purchase, error) {
+	return Dao.GetUnfinishedPurchases(c.dao.GetSteamID())
+}
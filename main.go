@@ -709,8 +709,14 @@ func TestGetMyListings(accountIndex int) {
 		return
 	}
 
-	client.GetMyListings()
-	// Logger.Infof("已上架物品 (%d 个) -> %+v\n", len(activeListings), activeListings)
+	listings, err := client.GetMyListings()
+	if err != nil {
+		Logger.Error(err)
+		return
+	}
+	Logger.Infof("已上架物品 (%d 个) -> %+v\n", len(listings.Active), listings.Active)
+	Logger.Infof("待确认物品 (%d 个) -> %+v\n", len(listings.Pending), listings.Pending)
+	Logger.Infof("订购单 (%d 个) -> %+v\n", len(listings.BuyOrders), listings.BuyOrders)
 }
 
 func TestGetInventory(accountIndex int) {
@@ -726,7 +732,7 @@ func TestGetInventory(accountIndex int) {
 	}
 
 	for _, item := range items {
-		Logger.Infof("物品ID: %s, 名称: %s, 市场名称: %s, 价格: %f, 货币: %d, 是否可交易: %t, 是否可在市场交易: %t", item.AssetID, item.Name, item.MarketName, item.Price, item.Currency, item.Tradable, item.Marketable)
+		Logger.Infof("物品ID: %s, 名称: %s, 市场名称: %s, 价格: %s, 是否可交易: %t, 是否可在市场交易: %t", item.AssetID, item.Name, item.MarketName, item.Price, item.Tradable, item.Marketable)
 	}
 }
 
@@ -743,7 +749,7 @@ func TestGetSteamGift(accountIndex int) {
 	}
 
 	for _, item := range items {
-		Logger.Infof("物品ID: %s, 名称: %s, 市场名称: %s, 价格: %f, 货币: %d, 是否可交易: %t, 是否可在市场交易: %t", item.AssetID, item.Name, item.MarketName, item.Price, item.Currency, item.Tradable, item.Marketable)
+		Logger.Infof("物品ID: %s, 名称: %s, 市场名称: %s, 价格: %s, 是否可交易: %t, 是否可在市场交易: %t", item.AssetID, item.Name, item.MarketName, item.Price, item.Tradable, item.Marketable)
 	}
 }
 
@@ -775,7 +781,7 @@ func TestPutList(accountIndex int) {
 		return
 	}
 
-	_, err = client.PutList(Constants.PrimalCarnage, Constants.PrimalCarnageCategory, "123123", 0.14, 23, string(data))
+	_, err = client.PutList(Constants.PrimalCarnage, Constants.PrimalCarnageCategory, "123123", Model.NewMoney(14, Model.CurrencyCNY), string(data))
 	if err != nil {
 		Logger.Error(err)
 		return
@@ -797,7 +803,7 @@ func TestBuyListing(accountIndex int) {
 	}
 
 	maFileContent := string(data)
-	Logger.Info(client.BuyListing("321360", "9079938361156157936", "", 0.16, 0.14, maFileContent).Error())
+	Logger.Info(client.BuyListing("321360", "9079938361156157936", "", Model.NewMoney(16, Model.CurrencyCNY), maFileContent).Error())
 }
 
 func TestRemoveMyListings(accountIndex int) {
@@ -857,7 +863,7 @@ func TestCreateOrder(accountIndex int) {
 
 	maFileContent := string(data)
 
-	Logger.Info(client.CreateOrder("Giftapult", 0.12, 15, maFileContent))
+	Logger.Info(client.CreateOrder("Giftapult", Model.NewMoney(12, Model.CurrencyCNY), 15, maFileContent))
 }
 
 func TestCheckAccountAvailable(accountIndex int) {